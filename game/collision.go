@@ -0,0 +1,55 @@
+package game
+
+// Collisions are checked after the player moves and again after each ghost
+// moves. The player moves first during a tick, so stepping onto the cell a
+// ghost is about to leave is a meeting, and so is swapping cells with a
+// ghost, which would otherwise let them walk through each other.
+
+// swept reports whether the player, standing on p after its move, meets a
+// ghost moving from g0 to g1 later in the same tick. They meet if the
+// player stepped onto the ghost's cell before it moved, which covers
+// swapping cells, or if the ghost moves onto the player. A ghost moving
+// into the cell the player just left is not a meeting.
+func swept(p, g0, g1 Position) bool {
+    return p == g0 || p == g1
+}
+
+// collidePlayer resolves meetings between the player, after its move, and
+// the ghosts on the cell it moved to. It reports whether the player was
+// caught.
+func (g *Game) collidePlayer() bool {
+    player := Position{g.player.row, g.player.col}
+    for _, ghost := range g.ghosts {
+        if player == (Position{ghost.position.row, ghost.position.col}) && g.meet(ghost) {
+            return true
+        }
+    }
+    return false
+}
+
+// collide resolves a meeting between the player and a ghost that just
+// moved from from. It reports whether the player was caught.
+func (g *Game) collide(ghost *ghost, from Position) bool {
+    player := Position{g.player.row, g.player.col}
+    to := Position{ghost.position.row, ghost.position.col}
+    if !swept(player, from, to) {
+        return false
+    }
+    return g.meet(ghost)
+}
+
+// meet resolves the player meeting a ghost. A normal ghost catches the
+// player, a frightened one is eaten. It reports whether the player was
+// caught.
+func (g *Game) meet(ghost *ghost) bool {
+    switch ghost.status {
+    case GhostStatusNormal:
+        // the player can only be caught once, however many ghosts are on
+        // the cell
+        g.loseLife()
+        return true
+    case GhostStatusBlue:
+        g.eatGhost(ghost)
+    }
+    return false
+}
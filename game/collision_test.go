@@ -0,0 +1,119 @@
+package game
+
+import "testing"
+
+func TestSwept(t *testing.T) {
+    var (
+        a = Position{1, 1}
+        b = Position{1, 2}
+        c = Position{1, 3}
+        d = Position{1, 4}
+    )
+    tests := []struct {
+        name      string
+        p, g0, g1 Position
+        want      bool
+    }{
+        {"on the same cell", a, a, a, true},
+        {"apart", a, b, b, false},
+        {"player moved onto still ghost", b, b, b, true},
+        {"ghost moves onto still player", b, a, b, true},
+        {"swap", b, b, a, true},
+        {"meet on the same cell", b, c, b, true},
+        {"player moved onto ghost as it moves on", b, b, c, true},
+        {"ghost moves where player was", c, a, b, false},
+        {"ghost moves away", a, c, d, false},
+        {"side by side", b, c, d, false},
+        {"ghost moves away from still player", a, b, c, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := swept(tt.p, tt.g0, tt.g1); got != tt.want {
+                t.Errorf("swept(%v, %v, %v) = %v, want %v", tt.p, tt.g0, tt.g1, got, tt.want)
+            }
+        })
+    }
+}
+
+// corridor is a straight corridor, with a dot far away so that the level is
+// not cleared
+var corridor = []string{
+    "##########",
+    "#        #",
+    "#######.##",
+    "##########",
+}
+
+func TestStepCollisions(t *testing.T) {
+    tests := []struct {
+        name string
+        // player and ghost columns in the corridor and directions, an
+        // empty direction keeps the sprite still
+        playerCol int
+        playerDir string
+        ghostCol  int
+        ghostDir  string
+        blue      bool
+        // what happens during the tick
+        wantCaught bool
+        wantEaten  bool
+    }{
+        {name: "player moves onto still ghost", playerCol: 3, playerDir: "RIGHT", ghostCol: 4, wantCaught: true},
+        {name: "ghost moves onto still player", playerCol: 4, ghostCol: 3, ghostDir: "RIGHT", wantCaught: true},
+        {name: "swap cells", playerCol: 3, playerDir: "RIGHT", ghostCol: 4, ghostDir: "LEFT", wantCaught: true},
+        {name: "meet on the same cell", playerCol: 3, playerDir: "RIGHT", ghostCol: 5, ghostDir: "LEFT", wantCaught: true},
+        {name: "player steps onto ghost as it moves on", playerCol: 3, playerDir: "RIGHT", ghostCol: 4, ghostDir: "RIGHT", wantCaught: true},
+        {name: "ghost follows player", playerCol: 4, playerDir: "RIGHT", ghostCol: 3, ghostDir: "RIGHT"},
+        {name: "move apart", playerCol: 3, playerDir: "LEFT", ghostCol: 4, ghostDir: "RIGHT"},
+        {name: "swap with frightened ghost", playerCol: 3, playerDir: "RIGHT", ghostCol: 4, ghostDir: "LEFT", blue: true, wantEaten: true},
+        {name: "player steps onto frightened ghost as it moves on", playerCol: 3, playerDir: "RIGHT", ghostCol: 4, ghostDir: "RIGHT", blue: true, wantEaten: true},
+        {name: "ghost moves onto still player while frightened", playerCol: 4, ghostCol: 3, ghostDir: "RIGHT", blue: true, wantEaten: true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            cfg := Config{Speeds: Speeds{Player: 1, Ghost: 1, GhostFrightened: 1, GhostEyes: 1}}
+            g := New(cfg, []Level{{Maze: corridor}}, 1)
+
+            g.player = sprite{1, tt.playerCol, 1, tt.playerCol}
+            g.playerDir = tt.playerDir
+            // the ghost's home is away from the action, so that an eaten
+            // ghost does not turn back into a normal one on the spot
+            gh := &ghost{position: sprite{1, tt.ghostCol, 1, 8}, status: GhostStatusNormal, dir: tt.ghostDir}
+            if tt.ghostDir == "" {
+                // keep the ghost still for this tick
+                gh.wait = 1
+            }
+            if tt.blue {
+                gh.status = GhostStatusBlue
+            }
+            g.ghosts = []*ghost{gh}
+
+            lives := g.lives
+            g.Step("")
+
+            if caught := g.lives < lives; caught != tt.wantCaught {
+                t.Errorf("caught = %v, want %v", caught, tt.wantCaught)
+            }
+            if eaten := gh.status == GhostStatusEyes; eaten != tt.wantEaten {
+                t.Errorf("eaten = %v, want %v", eaten, tt.wantEaten)
+            }
+        })
+    }
+}
+
+func TestStepLosesOneLifePerTick(t *testing.T) {
+    cfg := Config{Speeds: Speeds{Player: 1, Ghost: 1, GhostFrightened: 1, GhostEyes: 1}}
+    g := New(cfg, []Level{{Maze: corridor}}, 1)
+
+    g.player = sprite{1, 4, 1, 4}
+    g.ghosts = []*ghost{
+        {position: sprite{1, 3, 1, 3}, status: GhostStatusNormal, dir: "RIGHT"},
+        {position: sprite{1, 5, 1, 5}, status: GhostStatusNormal, dir: "LEFT"},
+    }
+
+    lives := g.lives
+    g.Step("")
+    if g.lives != lives-1 {
+        t.Errorf("lives = %d after two ghosts caught the player, want %d", g.lives, lives-1)
+    }
+}
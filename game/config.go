@@ -0,0 +1,115 @@
+package game
+
+import (
+    "encoding/json"
+    "os"
+    "time"
+)
+
+// Config holds the emoji configuration
+type Config struct {
+    Player           string         `json:"player"`
+    Ghost            string         `json:"ghost"`
+    Wall             string         `json:"wall"`
+    Dot              string         `json:"dot"`
+    Pill             string         `json:"pill"`
+    Death            string         `json:"death"`
+    DeathFrames      []string       `json:"death_frames"`
+    Space            string         `json:"space"`
+    UseEmoji         bool           `json:"use_emoji"`
+    GhostBlue        string         `json:"ghost_blue"`
+    GhostEyes        string         `json:"ghost_eyes"`
+    PillDurationSecs time.Duration  `json:"pill_duration_secs"`
+    StartingLives    int            `json:"starting_lives"`
+    ExtraLife        ExtraLife      `json:"extra_life"`
+    ModeSchedule     []ModeSchedule `json:"mode_schedule"`
+    Speeds           Speeds         `json:"speeds"`
+    GhostRelease     GhostRelease   `json:"ghost_release"`
+    FruitSpawn       FruitSpawn     `json:"fruit_spawn"`
+    // Fruits maps each fruit name (cherry, strawberry, orange, apple,
+    // melon, galaxian, bell, key) to its glyph
+    Fruits map[string]string `json:"fruits"`
+    // Keys lists the key names bound to each action (up, down, left,
+    // right, quit, pause)
+    Keys map[string][]string `json:"keys"`
+}
+
+// Speeds is the number of ticks each kind of sprite waits between two
+// moves; a higher number means a slower sprite. Zero values fall back to
+// the defaults.
+type Speeds struct {
+    Player          int `json:"player"`
+    Ghost           int `json:"ghost"`
+    GhostFrightened int `json:"ghost_frightened"`
+    GhostEyes       int `json:"ghost_eyes"`
+}
+
+func (s Speeds) withDefaults() Speeds {
+    if s.Player <= 0 {
+        s.Player = 4
+    }
+    if s.Ghost <= 0 {
+        s.Ghost = 4
+    }
+    if s.GhostFrightened <= 0 {
+        s.GhostFrightened = 6
+    }
+    if s.GhostEyes <= 0 {
+        s.GhostEyes = 2
+    }
+    return s
+}
+
+// LoadConfig reads a JSON configuration file
+func LoadConfig(file string) (Config, error) {
+    var cfg Config
+
+    f, err := os.Open(file)
+    if err != nil {
+        return cfg, err
+    }
+    defer f.Close()
+
+    decoder := json.NewDecoder(f)
+    err = decoder.Decode(&cfg)
+    if err != nil {
+        return cfg, err
+    }
+
+    return cfg, nil
+}
+
+// GhostRelease is the schedule letting ghosts out of the ghost house one at
+// a time. Dots lists how many dots must be eaten before each ghost in turn
+// is released; a ghost is also released after TimeoutSecs without a dot
+// being eaten.
+type GhostRelease struct {
+    Dots        []int `json:"dots"`
+    TimeoutSecs int   `json:"timeout_secs"`
+}
+
+// FruitSpawn controls the bonus fruit: one appears each time the number of
+// dots eaten on a level reaches an entry of Dots, and stays for
+// DurationSecs
+type FruitSpawn struct {
+    Dots         []int `json:"dots"`
+    DurationSecs int   `json:"duration_secs"`
+}
+
+// ExtraLife grants a bonus life when the score reaches Score, and again at
+// every multiple of Score if Repeat is set. A zero Score uses the arcade's
+// 10,000 points; a negative one disables extra lives.
+type ExtraLife struct {
+    Score  int  `json:"score"`
+    Repeat bool `json:"repeat"`
+}
+
+func (e ExtraLife) withDefaults() ExtraLife {
+    if e.Score == 0 {
+        e.Score = 10000
+    }
+    if e.Score < 0 {
+        e.Score = 0
+    }
+    return e
+}
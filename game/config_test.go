@@ -0,0 +1,55 @@
+package game
+
+import "testing"
+
+func TestSpeedsWithDefaults(t *testing.T) {
+    tests := []struct {
+        name   string
+        speeds Speeds
+        want   Speeds
+    }{
+        {name: "zero", want: Speeds{Player: 4, Ghost: 4, GhostFrightened: 6, GhostEyes: 2}},
+        {name: "negative", speeds: Speeds{Player: -1, Ghost: -1, GhostFrightened: -1, GhostEyes: -1}, want: Speeds{Player: 4, Ghost: 4, GhostFrightened: 6, GhostEyes: 2}},
+        {name: "set", speeds: Speeds{Player: 1, Ghost: 2, GhostFrightened: 3, GhostEyes: 5}, want: Speeds{Player: 1, Ghost: 2, GhostFrightened: 3, GhostEyes: 5}},
+        {name: "partly set", speeds: Speeds{Ghost: 3}, want: Speeds{Player: 4, Ghost: 3, GhostFrightened: 6, GhostEyes: 2}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := tt.speeds.withDefaults(); got != tt.want {
+                t.Errorf("withDefaults() = %+v, want %+v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestLevelTicks(t *testing.T) {
+    tests := []struct {
+        name            string
+        cfg             Config
+        level           Level
+        wantPill        int
+        wantGhostSpeed  int
+        wantPlayerSpeed int
+    }{
+        {name: "defaults", wantGhostSpeed: 4, wantPlayerSpeed: 4},
+        {name: "config", cfg: Config{PillDurationSecs: 6, Speeds: Speeds{Player: 2, Ghost: 3}}, wantPill: 6 * TickRate, wantGhostSpeed: 3, wantPlayerSpeed: 2},
+        {name: "level overrides", cfg: Config{PillDurationSecs: 6, Speeds: Speeds{Ghost: 3}}, level: Level{PillDurationSecs: 2, GhostSpeed: 1}, wantPill: 2 * TickRate, wantGhostSpeed: 1, wantPlayerSpeed: 4},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            lvl := tt.level
+            lvl.Maze = corridor
+            g := New(tt.cfg, []Level{lvl}, 1)
+
+            if g.pillDuration != tt.wantPill {
+                t.Errorf("pill duration = %d ticks, want %d", g.pillDuration, tt.wantPill)
+            }
+            if g.speeds.Ghost != tt.wantGhostSpeed {
+                t.Errorf("ghost speed = %d ticks, want %d", g.speeds.Ghost, tt.wantGhostSpeed)
+            }
+            if g.speeds.Player != tt.wantPlayerSpeed {
+                t.Errorf("player speed = %d ticks, want %d", g.speeds.Player, tt.wantPlayerSpeed)
+            }
+        })
+    }
+}
@@ -0,0 +1,87 @@
+package game
+
+// The sequence after the player is caught: everything freezes for a moment,
+// the death animation plays with the ghosts out of sight, then the player
+// and the ghosts go back to where they started and a READY! countdown runs
+// before play resumes.
+const (
+    deathFreezeTicks = TickRate / 2
+    // DeathAnimationTicks is how long the death animation lasts
+    DeathAnimationTicks = 3 * TickRate / 2
+    readyTicks          = 2 * TickRate
+)
+
+// loseLife tells everyone that the player was caught. The game itself
+// takes the life away when it handles the event, see startDeath.
+func (g *Game) loseLife() {
+    g.emit(LifeLost{
+        EventInfo: g.at(g.player.row, g.player.col),
+        Lives:     g.lives - 1,
+    })
+}
+
+// startDeath takes a life away and starts the death sequence, or ends the
+// game if that was the last life
+func (g *Game) startDeath() {
+    g.lives--
+    if g.lives > 0 {
+        g.deathTicks = deathFreezeTicks + DeathAnimationTicks
+    }
+}
+
+// updateDeath runs the death sequence and the READY! countdown. It reports
+// whether one of them is in progress, in which case nothing else moves.
+func (g *Game) updateDeath() bool {
+    if g.deathTicks > 0 {
+        g.deathTicks--
+        if g.deathTicks == 0 {
+            g.resetPositions()
+            g.readyTicks = readyTicks
+        }
+        return true
+    }
+    if g.readyTicks > 0 {
+        g.readyTicks--
+        return true
+    }
+    return false
+}
+
+// deathAnimation returns how far the death animation has got, from 0 when
+// it starts, or has not started yet, to 1 when it ends
+func (g *Game) deathAnimation() float64 {
+    if g.deathTicks == 0 || g.deathTicks > DeathAnimationTicks {
+        return 0
+    }
+    return float64(DeathAnimationTicks-g.deathTicks) / DeathAnimationTicks
+}
+
+// resetPositions puts the player and the ghosts back where they started,
+// cancels the power pill and takes away the bonus fruit. The dots eaten
+// stay eaten.
+func (g *Game) resetPositions() {
+    g.player.row, g.player.col = g.player.startRow, g.player.startCol
+    g.playerDir, g.nextDir = "", ""
+    g.playerWait = 0
+
+    wasFrightened := g.modes.mode() == ModeFrightened
+    g.modes.frightened = 0
+    g.ghostsEaten = 0
+    if wasFrightened {
+        g.emit(FrightenedEnded{g.at(g.player.row, g.player.col)})
+    }
+
+    for _, ghost := range g.ghosts {
+        ghost.position.row, ghost.position.col = ghost.position.startRow, ghost.position.startCol
+        ghost.status = GhostStatusNormal
+        ghost.dir = ""
+        ghost.wait = 0
+        ghost.house = houseOut
+        if g.house != nil && g.house.inside(Position{ghost.position.startRow, ghost.position.startCol}) {
+            ghost.house = houseWaiting
+        }
+    }
+    g.houseDots, g.houseTimer, g.released = 0, 0, 0
+
+    g.fruitTicks = 0
+}
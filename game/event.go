@@ -0,0 +1,162 @@
+package game
+
+// EventInfo is what every event carries: the tick it happened on and where
+type EventInfo struct {
+    Tick     int
+    Position Position
+}
+
+// Info returns the tick and position of the event
+func (e EventInfo) Info() EventInfo {
+    return e
+}
+
+// Event is something that happened during a Step. Use a type switch, or
+// On, to tell the kinds of events apart.
+type Event interface {
+    Info() EventInfo
+}
+
+// DotEaten is sent when the player eats a dot
+type DotEaten struct {
+    EventInfo
+}
+
+// PillEaten is sent when the player eats a power pill
+type PillEaten struct {
+    EventInfo
+}
+
+// GhostEaten is sent when the player eats a frightened ghost. Position is
+// where the ghost was caught.
+type GhostEaten struct {
+    EventInfo
+    Points int
+}
+
+// FruitEaten is sent when the player eats the bonus fruit
+type FruitEaten struct {
+    EventInfo
+    Fruit  string
+    Points int
+}
+
+// FrightenedEnded is sent when the ghosts stop being frightened. Position
+// is where the player is.
+type FrightenedEnded struct {
+    EventInfo
+}
+
+// ExtraLifeEarned is sent when the score earns the player an extra life
+type ExtraLifeEarned struct {
+    EventInfo
+    Lives int
+}
+
+// LifeLost is sent when a ghost catches the player. Lives is the number of
+// lives left.
+type LifeLost struct {
+    EventInfo
+    Lives int
+}
+
+// LevelCleared is sent when the last dot of a level is eaten
+type LevelCleared struct {
+    EventInfo
+    Level int
+}
+
+// GameOver is sent once, when the game ends
+type GameOver struct {
+    EventInfo
+    Won   bool
+    Score int
+}
+
+// subscriber is a function registered with Subscribe
+type subscriber struct {
+    id int
+    fn func(Event)
+}
+
+// Subscribe registers fn to be called with every event, in the order they
+// happen. fn is called from Step, so it runs on the goroutine driving the
+// game and must not call Step itself. The returned function unsubscribes.
+func (g *Game) Subscribe(fn func(Event)) (unsubscribe func()) {
+    g.nextSubscriber++
+    id := g.nextSubscriber
+    g.subscribers = append(g.subscribers, subscriber{id: id, fn: fn})
+
+    return func() {
+        for i, s := range g.subscribers {
+            if s.id == id {
+                g.subscribers = append(g.subscribers[:i:i], g.subscribers[i+1:]...)
+                return
+            }
+        }
+    }
+}
+
+// On registers fn to be called with every event of type E, such as
+// LifeLost. The returned function unsubscribes.
+func On[E Event](g *Game, fn func(E)) (unsubscribe func()) {
+    return g.Subscribe(func(e Event) {
+        if ev, ok := e.(E); ok {
+            fn(ev)
+        }
+    })
+}
+
+// emit lets the game itself react to e, then hands it to the subscribers.
+// Events raised while the game reacts to another one, such as the extra
+// life earned by the points of an eaten ghost, are queued and sent once
+// the subscribers have seen the event that caused them, so that
+// subscribers get every event in the order it happened.
+func (g *Game) emit(e Event) {
+    g.queued = append(g.queued, e)
+    if g.emitting {
+        return
+    }
+
+    g.emitting = true
+    defer func() { g.emitting = false }()
+    for len(g.queued) > 0 {
+        e := g.queued[0]
+        g.queued = g.queued[1:]
+
+        g.handle(e)
+        for _, s := range g.subscribers {
+            s.fn(e)
+        }
+    }
+}
+
+// handle keeps the score, the lives and the frightened mode up to date
+// with what happened
+func (g *Game) handle(e Event) {
+    switch e := e.(type) {
+    case DotEaten:
+        g.addScore(1)
+    case PillEaten:
+        g.addScore(10)
+        g.ghostsEaten = 0
+        from := g.modes.mode()
+        if g.modes.frighten(g.pillDuration) {
+            g.applyMode(from)
+        }
+        g.frightenGhosts()
+    case GhostEaten:
+        g.addScore(e.Points)
+        g.ghostsEaten++
+    case FruitEaten:
+        g.addScore(e.Points)
+        g.collected = append(g.collected, e.Fruit)
+    case LifeLost:
+        g.startDeath()
+    }
+}
+
+// at returns the event info for something happening now at row, col
+func (g *Game) at(row, col int) EventInfo {
+    return EventInfo{Tick: g.tick, Position: Position{row, col}}
+}
@@ -0,0 +1,140 @@
+package game
+
+import (
+    "fmt"
+    "reflect"
+    "testing"
+)
+
+// name describes an event for comparing event orders
+func name(e Event) string {
+    return fmt.Sprintf("%T", e)
+}
+
+func TestSubscribe(t *testing.T) {
+    g := New(Config{}, []Level{{Maze: corridor}}, 1)
+
+    var calls []string
+    unsubA := g.Subscribe(func(e Event) { calls = append(calls, "a "+name(e)) })
+    g.Subscribe(func(e Event) { calls = append(calls, "b "+name(e)) })
+    unsubC := g.Subscribe(func(e Event) { calls = append(calls, "c "+name(e)) })
+
+    g.emit(DotEaten{})
+    unsubA()
+    unsubA()
+    g.emit(PillEaten{})
+    unsubC()
+    g.emit(DotEaten{})
+
+    want := []string{
+        "a game.DotEaten", "b game.DotEaten", "c game.DotEaten",
+        "b game.PillEaten", "c game.PillEaten",
+        "b game.DotEaten",
+    }
+    if !reflect.DeepEqual(calls, want) {
+        t.Errorf("calls = %v, want %v", calls, want)
+    }
+}
+
+func TestOn(t *testing.T) {
+    g := New(Config{}, []Level{{Maze: corridor}}, 1)
+
+    var eaten []GhostEaten
+    unsubscribe := On(g, func(e GhostEaten) { eaten = append(eaten, e) })
+
+    g.emit(DotEaten{})
+    g.emit(GhostEaten{Points: 200})
+    unsubscribe()
+    g.emit(GhostEaten{Points: 400})
+
+    if len(eaten) != 1 || eaten[0].Points != 200 {
+        t.Errorf("GhostEaten events = %+v, want the one worth 200", eaten)
+    }
+}
+
+func TestEventsRaisedWhileHandlingComeAfter(t *testing.T) {
+    cfg := Config{
+        ExtraLife: ExtraLife{Score: 200},
+        Speeds:    Speeds{Player: 1, Ghost: 1, GhostFrightened: 1, GhostEyes: 1},
+    }
+    g := New(cfg, []Level{{Maze: corridor}}, 1)
+    g.player = sprite{1, 3, 1, 3}
+    g.playerDir = "RIGHT"
+    g.ghosts = []*ghost{{position: sprite{1, 4, 1, 8}, status: GhostStatusBlue, wait: 100}}
+
+    var got []string
+    var livesSeen []int
+    g.Subscribe(func(e Event) {
+        got = append(got, name(e))
+        livesSeen = append(livesSeen, g.lives)
+    })
+    g.Step("")
+
+    // the extra life is earned by the ghost's points, so subscribers hear
+    // of the ghost first
+    want := []string{"game.GhostEaten", "game.ExtraLifeEarned"}
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("events = %v, want %v", got, want)
+    }
+    // the game has handled all of them by the time subscribers are called
+    if !reflect.DeepEqual(livesSeen, []int{4, 4}) {
+        t.Errorf("lives seen by the subscriber = %v, want [4 4]", livesSeen)
+    }
+}
+
+func TestEventPayloads(t *testing.T) {
+    cfg := Config{Speeds: Speeds{Player: 1, Ghost: 1, GhostFrightened: 1, GhostEyes: 1}}
+    maze := []string{
+        "##########",
+        "#..    . #",
+        "##########",
+    }
+    g := New(cfg, []Level{{Maze: maze}}, 1)
+    g.player = sprite{1, 3, 1, 3}
+    g.ghosts = []*ghost{{position: sprite{1, 6, 1, 6}, status: GhostStatusNormal, wait: 100}}
+
+    var events []Event
+    g.Subscribe(func(e Event) { events = append(events, e) })
+
+    // two dots to the left, then back right into the ghost
+    for _, input := range []string{"LEFT", "", "RIGHT", "", "", "", ""} {
+        g.Step(input)
+    }
+
+    want := []Event{
+        DotEaten{EventInfo{Tick: 1, Position: Position{1, 2}}},
+        DotEaten{EventInfo{Tick: 2, Position: Position{1, 1}}},
+        LifeLost{EventInfo: EventInfo{Tick: 7, Position: Position{1, 6}}, Lives: 2},
+    }
+    if !reflect.DeepEqual(events, want) {
+        t.Errorf("events =\n%#v\nwant\n%#v", events, want)
+    }
+    if g.lives != 2 {
+        t.Errorf("lives = %d after LifeLost, want 2", g.lives)
+    }
+}
+
+func TestLastLifeAndGameOver(t *testing.T) {
+    cfg := Config{StartingLives: 1, Speeds: Speeds{Player: 1, Ghost: 1, GhostFrightened: 1, GhostEyes: 1}}
+    g := New(cfg, []Level{{Maze: corridor}}, 1)
+    g.player = sprite{1, 3, 1, 3}
+    g.playerDir = "RIGHT"
+    g.ghosts = []*ghost{{position: sprite{1, 4, 1, 4}, status: GhostStatusNormal, wait: 100}}
+
+    var events []Event
+    g.Subscribe(func(e Event) { events = append(events, e) })
+    g.Step("")
+    g.Step("")
+
+    info := EventInfo{Tick: 1, Position: Position{1, 4}}
+    want := []Event{
+        LifeLost{EventInfo: info, Lives: 0},
+        GameOver{EventInfo: info, Score: 0},
+    }
+    if !reflect.DeepEqual(events, want) {
+        t.Errorf("events =\n%#v\nwant\n%#v", events, want)
+    }
+    if s := g.Snapshot(); s.Dying || !g.IsOver() {
+        t.Errorf("dying = %v, over = %v after the last life, want no death sequence and the game over", s.Dying, g.IsOver())
+    }
+}
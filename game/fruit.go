@@ -0,0 +1,116 @@
+package game
+
+import "fmt"
+
+// fruitPoints is the score of each bonus fruit, in the order they appear
+// over the levels
+var fruitPoints = map[string]int{
+    "cherry":     100,
+    "strawberry": 300,
+    "orange":     500,
+    "apple":      700,
+    "melon":      1000,
+    "galaxian":   2000,
+    "bell":       3000,
+    "key":        5000,
+}
+
+// arcadeFruits is the fruit of each level when the level list does not
+// name one; every level past the end of the list gets a key
+var arcadeFruits = []string{
+    "cherry", "strawberry", "orange", "orange", "apple", "apple",
+    "melon", "melon", "galaxian", "galaxian", "bell", "bell", "key",
+}
+
+// defaultFruitDots is the number of dots eaten on a level after which a
+// fruit appears
+var defaultFruitDots = []int{70, 170}
+
+// defaultFruitDurationSecs is how long a fruit stays before disappearing
+const defaultFruitDurationSecs = 10
+
+// FruitView is a bonus fruit waiting to be eaten
+type FruitView struct {
+    Position
+    Name string
+}
+
+// fruitForLevel returns the fruit of a level, falling back to the arcade
+// table when the level does not name one
+func fruitForLevel(lvl Level, level int) string {
+    if lvl.Fruit != "" {
+        return lvl.Fruit
+    }
+    if level > len(arcadeFruits) {
+        return arcadeFruits[len(arcadeFruits)-1]
+    }
+    return arcadeFruits[level-1]
+}
+
+func checkFruit(name string) error {
+    if _, ok := fruitPoints[name]; name != "" && !ok {
+        return fmt.Errorf("unknown fruit %q", name)
+    }
+    return nil
+}
+
+// findFruitSpot returns the tile where fruit appears: the first open tile
+// below the ghost house, or the player start if the maze has no house
+func (g *Game) findFruitSpot() Position {
+    start := Position{g.player.startRow, g.player.startCol}
+    if g.house == nil {
+        return start
+    }
+
+    // walk down from the door, through the house and its bottom wall
+    col := g.house.door.Col
+    passedWall := false
+    for row := g.house.door.Row + 1; row < len(g.maze); row++ {
+        if g.maze[row][col] == '#' {
+            passedWall = true
+            continue
+        }
+        if passedWall && g.house.outside[Position{row, col}] {
+            return Position{row, col}
+        }
+    }
+    return start
+}
+
+// updateFruit makes fruit appear once enough dots have been eaten on the
+// level, and disappear again when its time is up
+func (g *Game) updateFruit() {
+    if g.fruitTicks > 0 {
+        g.fruitTicks--
+        return
+    }
+
+    dots := g.cfg.FruitSpawn.Dots
+    if len(dots) == 0 {
+        dots = defaultFruitDots
+    }
+    if g.fruitsSpawned >= len(dots) || g.levelDots < dots[g.fruitsSpawned] {
+        return
+    }
+
+    duration := g.cfg.FruitSpawn.DurationSecs
+    if duration <= 0 {
+        duration = defaultFruitDurationSecs
+    }
+    g.fruitsSpawned++
+    g.fruitTicks = duration * TickRate
+}
+
+// eatFruit scores the fruit if the player is standing on it
+func (g *Game) eatFruit() {
+    if g.fruitTicks == 0 || g.player.row != g.fruitSpot.Row || g.player.col != g.fruitSpot.Col {
+        return
+    }
+
+    g.fruitTicks = 0
+    g.emit(FruitEaten{
+        EventInfo: g.at(g.fruitSpot.Row, g.fruitSpot.Col),
+        Fruit:     g.fruit,
+        Points:    fruitPoints[g.fruit],
+    })
+}
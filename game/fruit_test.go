@@ -0,0 +1,111 @@
+package game
+
+import (
+    "reflect"
+    "testing"
+)
+
+// orchard has a ghost house with open floor below it, where fruit appears
+var orchard = []string{
+    "#########",
+    "#P......#",
+    "#.##-##.#",
+    "#.#G G#.#",
+    "#.#####.#",
+    "#... ...#",
+    "#########",
+}
+
+func TestFruitSpawnAndTimeout(t *testing.T) {
+    cfg := Config{FruitSpawn: FruitSpawn{Dots: []int{2, 4}, DurationSecs: 1}}
+    g := New(cfg, []Level{{Maze: orchard, Fruit: "melon"}}, 1)
+
+    g.levelDots = 1
+    g.updateFruit()
+    if f := g.Snapshot().Fruit; f != nil {
+        t.Fatalf("fruit %v after 1 dot, want none", f)
+    }
+
+    g.levelDots = 2
+    g.updateFruit()
+    want := &FruitView{Position: Position{5, 4}, Name: "melon"}
+    if f := g.Snapshot().Fruit; !reflect.DeepEqual(f, want) {
+        t.Fatalf("fruit after 2 dots = %v, want %v", f, want)
+    }
+
+    for i := 0; i < TickRate-1; i++ {
+        g.updateFruit()
+    }
+    if g.Snapshot().Fruit == nil {
+        t.Fatalf("fruit gone before its time was up")
+    }
+    g.updateFruit()
+    if f := g.Snapshot().Fruit; f != nil {
+        t.Fatalf("fruit %v still there after its time was up", f)
+    }
+
+    // the same threshold does not bring it back, the next one does
+    g.updateFruit()
+    if f := g.Snapshot().Fruit; f != nil {
+        t.Fatalf("fruit %v came back without more dots", f)
+    }
+    g.levelDots = 4
+    g.updateFruit()
+    if g.Snapshot().Fruit == nil {
+        t.Errorf("no fruit after 4 dots")
+    }
+}
+
+func TestEatFruit(t *testing.T) {
+    cfg := Config{Speeds: Speeds{Player: 1}}
+    g := New(cfg, []Level{{Maze: orchard}}, 1)
+    g.fruitTicks = 10
+    g.player = sprite{5, 3, 1, 1}
+
+    var eaten []FruitEaten
+    On(g, func(e FruitEaten) { eaten = append(eaten, e) })
+
+    score := g.score
+    g.Step("RIGHT")
+
+    s := g.Snapshot()
+    if s.Fruit != nil {
+        t.Errorf("fruit still shown after it was eaten")
+    }
+    if got := s.Score - score; got != fruitPoints["cherry"] {
+        t.Errorf("scored %d for the fruit, want %d", got, fruitPoints["cherry"])
+    }
+    if !reflect.DeepEqual(s.Collected, []string{"cherry"}) {
+        t.Errorf("collected = %v, want [cherry]", s.Collected)
+    }
+    if len(eaten) != 1 || eaten[0].Position != (Position{5, 4}) {
+        t.Errorf("FruitEaten events = %+v, want one at 5,4", eaten)
+    }
+}
+
+func TestFruitSpotWithoutHouse(t *testing.T) {
+    g := New(Config{}, []Level{{Maze: corridor}}, 1)
+    g.player = sprite{1, 1, 1, 3}
+    if got := g.findFruitSpot(); got != (Position{1, 3}) {
+        t.Errorf("findFruitSpot() = %v, want the player start", got)
+    }
+}
+
+func TestFruitForLevel(t *testing.T) {
+    tests := []struct {
+        lvl   Level
+        level int
+        want  string
+    }{
+        {Level{}, 1, "cherry"},
+        {Level{}, 3, "orange"},
+        {Level{}, 13, "key"},
+        {Level{}, 40, "key"},
+        {Level{Fruit: "bell"}, 1, "bell"},
+    }
+    for _, tt := range tests {
+        if got := fruitForLevel(tt.lvl, tt.level); got != tt.want {
+            t.Errorf("fruitForLevel(%+v, %d) = %q, want %q", tt.lvl, tt.level, got, tt.want)
+        }
+    }
+}
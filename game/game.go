@@ -0,0 +1,304 @@
+package game
+
+import (
+    "math/rand"
+    "time"
+)
+
+// TickRate is the number of simulation ticks per second of game time. All
+// game timing (movement speeds, pill duration, pauses) is counted in ticks,
+// so the simulation does not depend on how fast frames are drawn.
+const TickRate = 20
+
+// TickDuration is the amount of game time covered by a single Step
+const TickDuration = time.Second / TickRate
+
+// defaultStartingLives is the number of lives when Config does not set one
+const defaultStartingLives = 3
+
+// extraLifeFlashTicks is how long the extra life cue stays on
+const extraLifeFlashTicks = 2 * TickRate
+
+// define sprite struct to tracking 2D coordinates(row and column) information
+type sprite struct {
+    row      int
+    col      int
+    startRow int
+    startCol int
+}
+
+// Game holds the complete state of a single Pac-Man game
+type Game struct {
+    cfg     Config
+    maze    []string
+    player  sprite
+    ghosts  []*ghost
+    score   int
+    numDots int
+    lives   int
+    level   int
+    levels  []Level
+    won     bool
+    modes   *modeScheduler
+    speeds  Speeds
+
+    // frightened time given by a pill on the current level, in ticks
+    pillDuration int
+    // ticks left in the intermission before the next level
+    intermission int
+    tick         int
+    seed         int64
+    // all randomness in the game comes from rng, so that a game can be
+    // replayed from its seed
+    rng *rand.Rand
+
+    // direction requested by the player, kept until the player can turn
+    // that way
+    nextDir string
+    // ticks left before the player can move again
+    playerWait int
+    // ticks left in the death sequence and the READY! countdown after
+    // losing a life, see death.go
+    deathTicks int
+    readyTicks int
+
+    // direction the player keeps moving in every move, also used by the
+    // ghosts to predict where the player is heading
+    playerDir string
+
+    // number of ghosts eaten since the last pill was taken
+    ghostsEaten int
+
+    // bonus fruit state, see fruit.go
+    fruit         string
+    fruitSpot     Position
+    fruitTicks    int
+    fruitsSpawned int
+    levelDots     int
+    collected     []string
+
+    // score at which the next extra life is granted, 0 when there are no
+    // more extra lives to earn
+    nextExtraLife int
+    // ticks left showing the extra life cue
+    extraLifeFlash int
+
+    // functions registered with Subscribe
+    subscribers    []subscriber
+    nextSubscriber int
+    overSent       bool
+    // events waiting to be sent, see emit
+    queued   []Event
+    emitting bool
+
+    // ghost house state, see house.go
+    house      *ghostHouse
+    houseDots  int
+    houseTimer int
+    released   int
+}
+
+// New creates a game playing through the given levels. The mazes are
+// copied, so the same levels can be used to start several games. Games
+// created with the same seed and fed the same input behave identically.
+func New(cfg Config, levels []Level, seed int64) *Game {
+    g := &Game{
+        cfg:    cfg,
+        levels: levels,
+        lives:  cfg.StartingLives,
+        level:  1,
+        seed:   seed,
+        rng:    rand.New(rand.NewSource(seed)),
+    }
+    if g.lives <= 0 {
+        g.lives = defaultStartingLives
+    }
+    g.nextExtraLife = cfg.ExtraLife.withDefaults().Score
+    g.loadLevel()
+
+    return g
+}
+
+// Step advances the simulation by one tick. input is the key received
+// during the tick, or an empty string if there was none.
+func (g *Game) Step(input string) {
+    g.tick++
+    defer g.checkOver()
+
+    if input == "ESC" {
+        g.lives = 0
+        return
+    }
+    if input != "" {
+        g.nextDir = input
+    }
+
+    if g.intermission > 0 {
+        g.intermission--
+        if g.intermission == 0 {
+            g.level++
+            g.loadLevel()
+        }
+        return
+    }
+
+    if g.extraLifeFlash > 0 {
+        g.extraLifeFlash--
+    }
+
+    if g.updateDeath() {
+        return
+    }
+
+    if g.playerWait > 0 {
+        g.playerWait--
+    } else {
+        g.movePlayer()
+        g.playerWait = g.speeds.Player - 1
+        if g.numDots == 0 {
+            g.levelCleared()
+            return
+        }
+    }
+    if g.collidePlayer() {
+        return
+    }
+
+    from := g.modes.mode()
+    if g.modes.advance() {
+        g.applyMode(from)
+        if from == ModeFrightened {
+            g.emit(FrightenedEnded{g.at(g.player.row, g.player.col)})
+        }
+    }
+    g.updateHouse()
+    g.updateFruit()
+    g.moveGhosts()
+}
+
+// checkOver tells the subscribers when the game has just ended
+func (g *Game) checkOver() {
+    if g.IsOver() && !g.overSent {
+        g.overSent = true
+        g.emit(GameOver{
+            EventInfo: g.at(g.player.row, g.player.col),
+            Won:       g.won,
+            Score:     g.score,
+        })
+    }
+}
+
+// Seed returns the seed the game's random number generator was created with
+func (g *Game) Seed() int64 {
+    return g.seed
+}
+
+// Tick returns the number of ticks simulated so far
+func (g *Game) Tick() int {
+    return g.tick
+}
+
+// IsOver reports whether the game has ended, either because the last level
+// was cleared or because the player ran out of lives
+func (g *Game) IsOver() bool {
+    return g.won || g.lives <= 0
+}
+
+// makeMove moves one cell in dir. Walls and the ghost house door block the
+// move.
+func (g *Game) makeMove(oldRow, oldCol int, dir string) (newRow, newCol int) {
+    return g.moveThrough(oldRow, oldCol, dir, false)
+}
+
+// moveThrough is makeMove for ghosts that are allowed to cross the ghost
+// house door, i.e. ghosts leaving the house and eyes heading back in
+func (g *Game) moveThrough(oldRow, oldCol int, dir string, throughDoor bool) (newRow, newCol int) {
+    newRow, newCol = oldRow, oldCol
+
+    switch dir {
+    case "UP":
+        newRow = newRow - 1
+        if newRow < 0 {
+            // 再次回到最下面一行
+            newRow = len(g.maze) - 1
+        }
+    case "DOWN":
+        newRow = newRow + 1
+        if newRow == len(g.maze) {
+            newRow = 0
+        }
+    case "RIGHT":
+        newCol = newCol + 1
+        if newCol == len(g.maze[0]) {
+            newCol = 0
+        }
+    case "LEFT":
+        newCol = newCol - 1
+        if newCol < 0 {
+            newCol = len(g.maze[0]) - 1
+        }
+    }
+
+    // 先尝试移动，如果新的位置碰巧遇到墙（#），则移动呗取消
+    if tile := g.maze[newRow][newCol]; tile == '#' || (tile == '-' && !throughDoor) {
+        newRow = oldRow
+        newCol = oldCol
+    }
+
+    return
+}
+
+// addScore adds points to the score, granting an extra life each time the
+// score reaches the configured threshold
+func (g *Game) addScore(points int) {
+    g.score += points
+
+    for g.nextExtraLife > 0 && g.score >= g.nextExtraLife {
+        g.lives++
+        g.extraLifeFlash = extraLifeFlashTicks
+        g.emit(ExtraLifeEarned{
+            EventInfo: g.at(g.player.row, g.player.col),
+            Lives:     g.lives,
+        })
+
+        extra := g.cfg.ExtraLife.withDefaults()
+        if extra.Repeat {
+            g.nextExtraLife += extra.Score
+        } else {
+            g.nextExtraLife = 0
+        }
+    }
+}
+
+// movePlayer moves the player one cell in its current direction. A queued
+// turn is taken as soon as the cell in that direction is open; until then
+// the player keeps going straight, or stands still against a wall.
+func (g *Game) movePlayer() {
+    if g.nextDir != "" {
+        row, col := g.makeMove(g.player.row, g.player.col, g.nextDir)
+        if row != g.player.row || col != g.player.col {
+            g.playerDir, g.nextDir = g.nextDir, ""
+        }
+    }
+    g.player.row, g.player.col = g.makeMove(g.player.row, g.player.col, g.playerDir)
+
+    // Remove dot from maze
+    removeDot := func(row, col int) {
+        g.maze[row] = g.maze[row][0:col] + " " + g.maze[row][col+1:]
+    }
+
+    switch g.maze[g.player.row][g.player.col] {
+    case '.':
+        g.numDots--
+        g.houseDots++
+        g.houseTimer = 0
+        g.levelDots++
+        removeDot(g.player.row, g.player.col)
+        g.emit(DotEaten{g.at(g.player.row, g.player.col)})
+    case 'X':
+        removeDot(g.player.row, g.player.col)
+        g.emit(PillEaten{g.at(g.player.row, g.player.col)})
+    }
+
+    g.eatFruit()
+}
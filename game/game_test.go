@@ -0,0 +1,111 @@
+package game
+
+import "testing"
+
+// junction is a corridor with a single opening downwards, and a dot out of
+// the way so that the level is not cleared
+var junction = []string{
+    "#######",
+    "#    .#",
+    "### ###",
+    "### ###",
+    "#######",
+}
+
+func TestQueuedTurnAtNextOpening(t *testing.T) {
+    cfg := Config{Speeds: Speeds{Player: 1, Ghost: 1, GhostFrightened: 1, GhostEyes: 1}}
+    g := New(cfg, []Level{{Maze: junction}}, 1)
+    g.player = sprite{1, 1, 1, 1}
+    g.playerDir = "RIGHT"
+
+    // the turn is asked for two cells before the opening, the player
+    // keeps going right until it can take it
+    want := []Position{{1, 2}, {1, 3}, {2, 3}, {3, 3}, {3, 3}}
+    for i, pos := range want {
+        input := ""
+        if i == 0 {
+            input = "DOWN"
+        }
+        g.Step(input)
+        if got := g.Snapshot().Player; got != pos {
+            t.Fatalf("player at %v after %d ticks, want %v", got, i+1, pos)
+        }
+    }
+    if g.playerDir != "DOWN" || g.nextDir != "" {
+        t.Errorf("direction = %q, queued %q, want DOWN and nothing queued", g.playerDir, g.nextDir)
+    }
+}
+
+func TestQueuedTurnReplacedByNewerKey(t *testing.T) {
+    cfg := Config{Speeds: Speeds{Player: 1, Ghost: 1, GhostFrightened: 1, GhostEyes: 1}}
+    g := New(cfg, []Level{{Maze: junction}}, 1)
+    g.player = sprite{1, 1, 1, 1}
+    g.playerDir = "RIGHT"
+
+    // UP never opens up, so it is still queued when LEFT replaces it
+    g.Step("UP")
+    g.Step("LEFT")
+    if got := g.Snapshot().Player; got != (Position{1, 1}) {
+        t.Errorf("player at %v, want back at 1,1", got)
+    }
+    if g.playerDir != "LEFT" {
+        t.Errorf("direction = %q, want LEFT", g.playerDir)
+    }
+}
+
+func TestExtraLife(t *testing.T) {
+    tests := []struct {
+        name      string
+        extra     ExtraLife
+        points    []int
+        wantLives int
+    }{
+        {name: "below the threshold", extra: ExtraLife{Score: 100}, points: []int{99}, wantLives: 3},
+        {name: "once", extra: ExtraLife{Score: 100}, points: []int{50, 60, 200, 500}, wantLives: 4},
+        {name: "repeat", extra: ExtraLife{Score: 100, Repeat: true}, points: []int{50, 60, 90}, wantLives: 5},
+        {name: "repeat, several at once", extra: ExtraLife{Score: 100, Repeat: true}, points: []int{350}, wantLives: 6},
+        {name: "arcade default", points: []int{9999, 1, 10000}, wantLives: 4},
+        {name: "disabled", extra: ExtraLife{Score: -1}, points: []int{100000}, wantLives: 3},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            g := New(Config{ExtraLife: tt.extra}, []Level{{Maze: corridor}}, 1)
+
+            var earned []ExtraLifeEarned
+            On(g, func(e ExtraLifeEarned) { earned = append(earned, e) })
+
+            for _, p := range tt.points {
+                g.addScore(p)
+            }
+            s := g.Snapshot()
+            if s.Lives != tt.wantLives {
+                t.Errorf("lives = %d, want %d", s.Lives, tt.wantLives)
+            }
+            if len(earned) != tt.wantLives-3 {
+                t.Errorf("%d ExtraLifeEarned events, want %d", len(earned), tt.wantLives-3)
+            }
+            for i, e := range earned {
+                if e.Lives != 4+i {
+                    t.Errorf("event %d has %d lives, want %d", i, e.Lives, 4+i)
+                }
+            }
+            if s.ExtraLife != (len(earned) > 0) {
+                t.Errorf("extra life cue = %v, want %v", s.ExtraLife, len(earned) > 0)
+            }
+        })
+    }
+}
+
+func TestExtraLifeCueTimesOut(t *testing.T) {
+    g := New(Config{ExtraLife: ExtraLife{Score: 1}}, []Level{{Maze: corridor}}, 1)
+    g.addScore(1)
+    for i := 0; i < extraLifeFlashTicks; i++ {
+        if !g.Snapshot().ExtraLife {
+            t.Fatalf("extra life cue gone after %d ticks", i)
+        }
+        g.Step("")
+    }
+    if g.Snapshot().ExtraLife {
+        t.Errorf("extra life cue still on after %d ticks", extraLifeFlashTicks)
+    }
+}
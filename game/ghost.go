@@ -0,0 +1,278 @@
+package game
+
+type GhostStatus string
+
+const (
+    GhostStatusNormal GhostStatus = "Normal"
+    GhostStatusBlue   GhostStatus = "Blue"
+    GhostStatusEyes   GhostStatus = "Eyes"
+)
+
+// ghostPoints is the score for each ghost eaten during a single pill,
+// doubling with every ghost: 200, 400, 800, 1600
+var ghostPoints = []int{200, 400, 800, 1600}
+
+// personality decides which tile a ghost is chasing
+type personality int
+
+const (
+    blinky personality = iota // chases the player directly
+    pinky                     // ambushes 4 tiles ahead of the player
+    inky                      // flanks using a vector from Blinky
+    clyde                     // chases from afar, scatters when close
+    numPersonalities
+)
+
+// clydeShyDistance is how close Clyde gets before giving up the chase
+const clydeShyDistance = 8
+
+type ghost struct {
+    position    sprite
+    status      GhostStatus
+    personality personality
+    dir         string
+    // ticks left before the ghost can move again
+    wait int
+    // where the ghost is relative to the ghost house
+    house houseState
+}
+
+var opposite = map[string]string{
+    "UP":    "DOWN",
+    "DOWN":  "UP",
+    "LEFT":  "RIGHT",
+    "RIGHT": "LEFT",
+}
+
+// dirVector returns the row and column offsets of one step in dir
+func dirVector(dir string) (int, int) {
+    switch dir {
+    case "UP":
+        return -1, 0
+    case "DOWN":
+        return 1, 0
+    case "LEFT":
+        return 0, -1
+    case "RIGHT":
+        return 0, 1
+    }
+    return 0, 0
+}
+
+func distance2(a, b Position) int {
+    dr, dc := a.Row-b.Row, a.Col-b.Col
+    return dr*dr + dc*dc
+}
+
+// scatterCorner is the corner of the maze each personality retreats to
+func (g *Game) scatterCorner(p personality) Position {
+    bottom, right := len(g.maze)-1, len(g.maze[0])-1
+    switch p {
+    case blinky:
+        return Position{0, right}
+    case pinky:
+        return Position{0, 0}
+    case inky:
+        return Position{bottom, right}
+    default:
+        return Position{bottom, 0}
+    }
+}
+
+// chaseTarget returns the tile a ghost is heading for while chasing
+func (g *Game) chaseTarget(gh *ghost) Position {
+    player := Position{g.player.row, g.player.col}
+    dr, dc := dirVector(g.playerDir)
+
+    switch gh.personality {
+    case pinky:
+        return Position{player.Row + 4*dr, player.Col + 4*dc}
+    case inky:
+        pivot := Position{player.Row + 2*dr, player.Col + 2*dc}
+        leader := player
+        for _, other := range g.ghosts {
+            if other.personality == blinky {
+                leader = Position{other.position.row, other.position.col}
+                break
+            }
+        }
+        return Position{2*pivot.Row - leader.Row, 2*pivot.Col - leader.Col}
+    case clyde:
+        pos := Position{gh.position.row, gh.position.col}
+        if distance2(pos, player) <= clydeShyDistance*clydeShyDistance {
+            return g.scatterCorner(clyde)
+        }
+        return player
+    default:
+        return player
+    }
+}
+
+// ghostOptions returns the directions a ghost may take from its current
+// tile. Ghosts never reverse unless they are in a dead end.
+func (g *Game) ghostOptions(gh *ghost) []string {
+    var options []string
+    for _, dir := range directions {
+        if gh.dir != "" && dir == opposite[gh.dir] {
+            continue
+        }
+        row, col := g.makeMove(gh.position.row, gh.position.col, dir)
+        if row != gh.position.row || col != gh.position.col {
+            options = append(options, dir)
+        }
+    }
+
+    if len(options) == 0 && gh.dir != "" {
+        options = append(options, opposite[gh.dir])
+    }
+    return options
+}
+
+// chooseDirection picks the option leading closest to the target tile. Ties
+// are broken in the order up, left, down, right.
+func (g *Game) chooseDirection(gh *ghost, options []string, target Position) string {
+    best, bestDist := "", -1
+    for _, dir := range options {
+        row, col := g.makeMove(gh.position.row, gh.position.col, dir)
+        d := distance2(Position{row, col}, target)
+        if bestDist < 0 || d < bestDist {
+            best, bestDist = dir, d
+        }
+    }
+    return best
+}
+
+// applyMode updates the ghosts after the mode switched away from from:
+// they turn blue or back to normal, and the ghosts out in the maze reverse
+// direction. As in the arcade, ghosts do not reverse when frightened mode
+// ends, and ghosts in the ghost house carry on towards the door.
+func (g *Game) applyMode(from Mode) {
+    status := GhostStatusNormal
+    if g.modes.mode() == ModeFrightened {
+        status = GhostStatusBlue
+    }
+
+    for _, ghost := range g.ghosts {
+        // eyes keep heading home until they reach the ghost house
+        if ghost.status == GhostStatusEyes {
+            continue
+        }
+        ghost.status = status
+        if from != ModeFrightened && ghost.house == houseOut {
+            ghost.dir = opposite[ghost.dir]
+        }
+    }
+}
+
+// frightenGhosts turns blue the ghosts out in the maze while frightened
+// mode runs. Every pill does this, so a ghost eaten earlier that has come
+// back out is frightened again when another pill restarts the timer.
+func (g *Game) frightenGhosts() {
+    if g.modes.mode() != ModeFrightened {
+        return
+    }
+    for _, ghost := range g.ghosts {
+        if ghost.status != GhostStatusEyes && ghost.house == houseOut {
+            ghost.status = GhostStatusBlue
+        }
+    }
+}
+
+// moveGhosts moves every ghost in turn, checking for a collision with the
+// player after each move. It stops as soon as the player is caught.
+func (g *Game) moveGhosts() {
+    for _, ghost := range g.ghosts {
+        from := Position{ghost.position.row, ghost.position.col}
+        g.moveGhost(ghost)
+        if g.collide(ghost, from) {
+            return
+        }
+    }
+}
+
+// moveGhost moves a ghost one cell, if it is its turn to move
+func (g *Game) moveGhost(ghost *ghost) {
+    if ghost.wait > 0 {
+        ghost.wait--
+        return
+    }
+    ghost.wait = g.ghostSpeed(ghost) - 1
+
+    if ghost.status == GhostStatusEyes {
+        g.moveEyes(ghost)
+        return
+    }
+
+    switch ghost.house {
+    case houseWaiting:
+        return
+    case houseLeaving:
+        g.leaveHouse(ghost)
+        return
+    }
+
+    options := g.ghostOptions(ghost)
+    if len(options) == 0 {
+        return
+    }
+
+    var dir string
+    switch {
+    case ghost.status == GhostStatusBlue:
+        // frightened ghosts wander aimlessly
+        dir = options[g.rng.Intn(len(options))]
+    case g.modes.mode() == ModeScatter:
+        dir = g.chooseDirection(ghost, options, g.scatterCorner(ghost.personality))
+    default:
+        dir = g.chooseDirection(ghost, options, g.chaseTarget(ghost))
+    }
+
+    ghost.dir = dir
+    ghost.position.row, ghost.position.col = g.makeMove(ghost.position.row, ghost.position.col, dir)
+}
+
+// ghostSpeed returns the number of ticks between two moves of a ghost
+func (g *Game) ghostSpeed(ghost *ghost) int {
+    switch ghost.status {
+    case GhostStatusBlue:
+        return g.speeds.GhostFrightened
+    case GhostStatusEyes:
+        return g.speeds.GhostEyes
+    default:
+        return g.speeds.Ghost
+    }
+}
+
+// eatGhost scores a frightened ghost and sends it back home as eyes
+func (g *Game) eatGhost(ghost *ghost) {
+    idx := g.ghostsEaten
+    if idx >= len(ghostPoints) {
+        idx = len(ghostPoints) - 1
+    }
+    ghost.status = GhostStatusEyes
+    g.emit(GhostEaten{
+        EventInfo: g.at(ghost.position.row, ghost.position.col),
+        Points:    ghostPoints[idx],
+    })
+}
+
+// moveEyes takes an eaten ghost one step closer to its starting position
+// in the ghost house, through the door, where it turns back into a normal
+// ghost and leaves the house again
+func (g *Game) moveEyes(ghost *ghost) {
+    pos := Position{ghost.position.row, ghost.position.col}
+    home := Position{ghost.position.startRow, ghost.position.startCol}
+
+    dir := g.nextStepTowards(pos, home, true)
+    ghost.position.row, ghost.position.col = g.moveThrough(pos.Row, pos.Col, dir, true)
+    if dir != "" {
+        ghost.dir = dir
+    }
+
+    if ghost.position.row == home.Row && ghost.position.col == home.Col {
+        ghost.status = GhostStatusNormal
+        if g.house != nil && g.house.inside(home) {
+            ghost.house = houseLeaving
+        }
+    }
+}
@@ -0,0 +1,68 @@
+package game
+
+import "testing"
+
+// open is an empty 20x20 room
+var open = func() []string {
+    maze := make([]string, 20)
+    maze[0] = "####################"
+    for i := 1; i < 19; i++ {
+        maze[i] = "#                  #"
+    }
+    maze[19] = maze[0]
+    return maze
+}()
+
+func TestChaseTarget(t *testing.T) {
+    tests := []struct {
+        name        string
+        personality personality
+        ghost       Position
+        // where Blinky is, for Inky
+        blinky    Position
+        playerDir string
+        want      Position
+    }{
+        {name: "blinky chases the player", personality: blinky, ghost: Position{1, 1}, playerDir: "UP", want: Position{10, 10}},
+        {name: "pinky ambushes ahead", personality: pinky, ghost: Position{1, 1}, playerDir: "UP", want: Position{6, 10}},
+        {name: "pinky ambushes ahead to the left", personality: pinky, ghost: Position{1, 1}, playerDir: "LEFT", want: Position{10, 6}},
+        {name: "pinky with the player still", personality: pinky, ghost: Position{1, 1}, want: Position{10, 10}},
+        // the pivot is 2 tiles right of the player, at 10,12, and Blinky
+        // at 8,9 is mirrored across it
+        {name: "inky flanks", personality: inky, ghost: Position{1, 1}, blinky: Position{8, 9}, playerDir: "RIGHT", want: Position{12, 15}},
+        {name: "clyde chases from afar", personality: clyde, ghost: Position{1, 1}, playerDir: "UP", want: Position{10, 10}},
+        {name: "clyde shies away when close", personality: clyde, ghost: Position{10, 3}, playerDir: "UP", want: Position{19, 0}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            g := New(Config{}, []Level{{Maze: open}}, 1)
+            g.player = sprite{10, 10, 10, 10}
+            g.playerDir = tt.playerDir
+
+            gh := &ghost{position: sprite{tt.ghost.Row, tt.ghost.Col, 1, 1}, personality: tt.personality}
+            g.ghosts = []*ghost{gh}
+            if tt.personality == inky {
+                g.ghosts = append(g.ghosts, &ghost{position: sprite{tt.blinky.Row, tt.blinky.Col, 1, 1}, personality: blinky})
+            }
+
+            if got := g.chaseTarget(gh); got != tt.want {
+                t.Errorf("chaseTarget() = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestScatterCorner(t *testing.T) {
+    g := New(Config{}, []Level{{Maze: open}}, 1)
+    want := map[personality]Position{
+        blinky: {0, 19},
+        pinky:  {0, 0},
+        inky:   {19, 19},
+        clyde:  {19, 0},
+    }
+    for p, corner := range want {
+        if got := g.scatterCorner(p); got != corner {
+            t.Errorf("scatterCorner(%d) = %v, want %v", p, got, corner)
+        }
+    }
+}
@@ -0,0 +1,116 @@
+package game
+
+// houseState is where a ghost is relative to the ghost house
+type houseState int
+
+const (
+    houseOut     houseState = iota // roaming the maze
+    houseWaiting                   // inside, waiting to be released
+    houseLeaving                   // released, heading out through the door
+)
+
+// defaultReleaseDots is the number of dots the player must eat before each
+// ghost in turn leaves the house; ghosts past the end of the list use the
+// last entry
+var defaultReleaseDots = []int{0, 0, 30, 60}
+
+// defaultReleaseTimeoutSecs releases the next ghost when the player has not
+// eaten a dot for that long
+const defaultReleaseTimeoutSecs = 4
+
+// ghostHouse is the pen drawn with '-' door tiles. Only ghosts can cross
+// the door: on their way out once released, and as eyes on their way back.
+type ghostHouse struct {
+    door Position
+    // exit is the tile just outside the door
+    exit Position
+    // outside holds every tile reachable without crossing the door
+    outside map[Position]bool
+}
+
+func (h *ghostHouse) inside(p Position) bool {
+    return !h.outside[p]
+}
+
+// findHouse locates the ghost house door of the current maze. It returns nil
+// when the maze has no door, in which case ghosts roam freely from the
+// start.
+func (g *Game) findHouse() *ghostHouse {
+    outside := reachable(g.maze, Position{g.player.startRow, g.player.startCol})
+
+    for row, line := range g.maze {
+        for col := 0; col < len(line); col++ {
+            if line[col] != '-' {
+                continue
+            }
+
+            door := Position{row, col}
+            for _, dir := range directions {
+                r, c := g.moveThrough(row, col, dir, true)
+                if outside[Position{r, c}] {
+                    return &ghostHouse{door: door, exit: Position{r, c}, outside: outside}
+                }
+            }
+        }
+    }
+
+    return nil
+}
+
+// releaseLimit returns the number of dots to eat before the next ghost is
+// let out
+func (g *Game) releaseLimit() int {
+    dots := g.cfg.GhostRelease.Dots
+    if len(dots) == 0 {
+        dots = defaultReleaseDots
+    }
+    if g.released < len(dots) {
+        return dots[g.released]
+    }
+    return dots[len(dots)-1]
+}
+
+// updateHouse lets the next waiting ghost out once the player has eaten
+// enough dots, or has not eaten any for a while. At most one ghost is
+// released per tick.
+func (g *Game) updateHouse() {
+    var next *ghost
+    for _, ghost := range g.ghosts {
+        if ghost.house == houseWaiting {
+            next = ghost
+            break
+        }
+    }
+    if next == nil {
+        return
+    }
+
+    timeout := g.cfg.GhostRelease.TimeoutSecs
+    if timeout <= 0 {
+        timeout = defaultReleaseTimeoutSecs
+    }
+
+    g.houseTimer++
+    if g.houseDots >= g.releaseLimit() || g.houseTimer >= timeout*TickRate {
+        next.house = houseLeaving
+        g.released++
+        g.houseDots = 0
+        g.houseTimer = 0
+    }
+}
+
+// leaveHouse moves a released ghost one step towards the door and out of
+// the house
+func (g *Game) leaveHouse(ghost *ghost) {
+    pos := Position{ghost.position.row, ghost.position.col}
+
+    dir := g.nextStepTowards(pos, g.house.exit, true)
+    ghost.position.row, ghost.position.col = g.moveThrough(pos.Row, pos.Col, dir, true)
+    if dir != "" {
+        ghost.dir = dir
+    }
+
+    if ghost.position.row == g.house.exit.Row && ghost.position.col == g.house.exit.Col {
+        ghost.house = houseOut
+    }
+}
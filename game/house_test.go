@@ -0,0 +1,95 @@
+package game
+
+import "testing"
+
+// pen has a ghost house under a corridor, with its door right below the
+// middle of the corridor
+var pen = []string{
+    "#########",
+    "#P..... #",
+    "####-####",
+    "##G   G##",
+    "#########",
+}
+
+func TestDoorBlocksPlayer(t *testing.T) {
+    g := New(Config{}, []Level{{Maze: pen}}, 1)
+
+    if row, col := g.makeMove(1, 4, "DOWN"); row != 1 || col != 4 {
+        t.Errorf("player moved through the door to %d,%d", row, col)
+    }
+}
+
+func TestDoorBlocksGhostsInTheMaze(t *testing.T) {
+    g := New(Config{}, []Level{{Maze: pen}}, 1)
+    for _, status := range []GhostStatus{GhostStatusNormal, GhostStatusBlue} {
+        gh := &ghost{position: sprite{1, 4, 3, 4}, status: status, dir: "RIGHT"}
+        for _, dir := range g.ghostOptions(gh) {
+            if dir == "DOWN" {
+                t.Errorf("%v ghost may move through the door", status)
+            }
+        }
+    }
+}
+
+func TestDoorAdmitsEyes(t *testing.T) {
+    g := New(Config{}, []Level{{Maze: pen}}, 1)
+    gh := &ghost{position: sprite{1, 5, 3, 4}, status: GhostStatusEyes, dir: "RIGHT"}
+
+    want := []Position{{1, 4}, {2, 4}, {3, 4}}
+    for _, pos := range want {
+        g.moveEyes(gh)
+        if got := (Position{gh.position.row, gh.position.col}); got != pos {
+            t.Fatalf("eyes at %v, want %v", got, pos)
+        }
+    }
+    if gh.status != GhostStatusNormal || gh.house != houseLeaving {
+        t.Errorf("ghost back home is %v, house state %d, want %v leaving the house", gh.status, gh.house, GhostStatusNormal)
+    }
+}
+
+func TestReleasedGhostLeavesThroughDoor(t *testing.T) {
+    g := New(Config{}, []Level{{Maze: pen}}, 1)
+    gh := g.ghosts[0]
+    if gh.house != houseWaiting {
+        t.Fatalf("ghost in the house starts in state %d, want waiting", gh.house)
+    }
+    gh.house = houseLeaving
+
+    for i := 0; i < 10 && gh.house == houseLeaving; i++ {
+        g.leaveHouse(gh)
+    }
+    if got := (Position{gh.position.row, gh.position.col}); gh.house != houseOut || got != g.house.exit {
+        t.Errorf("ghost at %v in state %d, want out at %v", got, gh.house, g.house.exit)
+    }
+}
+
+func TestReleaseByDotsAndTimeout(t *testing.T) {
+    cfg := Config{GhostRelease: GhostRelease{Dots: []int{0, 3}, TimeoutSecs: 1}}
+    g := New(cfg, []Level{{Maze: pen}}, 1)
+    first, second := g.ghosts[0], g.ghosts[1]
+
+    g.updateHouse()
+    if first.house != houseLeaving || second.house != houseWaiting {
+        t.Fatalf("after the first tick: states %d, %d, want the first ghost released", first.house, second.house)
+    }
+
+    g.houseDots = 3
+    g.updateHouse()
+    if second.house != houseLeaving {
+        t.Errorf("second ghost not released after 3 dots")
+    }
+
+    // without dots, the timeout lets the next ghost out
+    second.house = houseWaiting
+    for i := 0; i < TickRate-1; i++ {
+        g.updateHouse()
+    }
+    if second.house != houseWaiting {
+        t.Fatalf("second ghost released before the timeout")
+    }
+    g.updateHouse()
+    if second.house != houseLeaving {
+        t.Errorf("second ghost not released after the timeout")
+    }
+}
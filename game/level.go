@@ -0,0 +1,151 @@
+package game
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "time"
+)
+
+// intermissionTicks is how long the "level N" screen stays up between levels
+const intermissionTicks = 2 * TickRate
+
+// Level describes one stage of the game. Zero values fall back to the
+// settings in Config.
+type Level struct {
+    MazeFile         string        `json:"maze"`
+    GhostSpeed       int           `json:"ghost_speed"`
+    PillDurationSecs time.Duration `json:"pill_duration_secs"`
+    Fruit            string        `json:"fruit"`
+
+    // Maze holds the rows loaded from MazeFile
+    Maze []string `json:"-"`
+}
+
+// LoadLevels reads a JSON list of levels and loads the maze of each one.
+// Maze paths are relative to the directory of the levels file.
+func LoadLevels(file string) ([]Level, error) {
+    f, err := os.Open(file)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    var levels []Level
+    decoder := json.NewDecoder(f)
+    err = decoder.Decode(&levels)
+    if err != nil {
+        return nil, err
+    }
+    if len(levels) == 0 {
+        return nil, fmt.Errorf("%s: no levels", file)
+    }
+
+    dir := filepath.Dir(file)
+    for i := range levels {
+        path := levels[i].MazeFile
+        if !filepath.IsAbs(path) {
+            path = filepath.Join(dir, path)
+        }
+        levels[i].Maze, err = LoadMaze(path)
+        if err != nil {
+            return nil, fmt.Errorf("level %d: %w", i+1, err)
+        }
+        if err := checkFruit(levels[i].Fruit); err != nil {
+            return nil, fmt.Errorf("level %d: %w", i+1, err)
+        }
+    }
+
+    return levels, nil
+}
+
+// SingleLevel returns a one level game on the given maze
+func SingleLevel(mazeFile string, maze []string) []Level {
+    return []Level{{MazeFile: mazeFile, Maze: maze}}
+}
+
+// HashLevels returns a hex encoded SHA-256 of the levels' settings and mazes
+func HashLevels(levels []Level) string {
+    h := sha256.New()
+    for _, l := range levels {
+        fmt.Fprintf(h, "%d %d %s\n", l.GhostSpeed, l.PillDurationSecs, l.Fruit)
+        h.Write([]byte(strings.Join(l.Maze, "\n")))
+        h.Write([]byte{0})
+    }
+    return hex.EncodeToString(h.Sum(nil))
+}
+
+// loadLevel sets up the maze, sprites and timers of the current level.
+// Score and lives carry over from the previous level.
+func (g *Game) loadLevel() {
+    idx := g.level - 1
+    if idx >= len(g.levels) {
+        idx = len(g.levels) - 1
+    }
+    lvl := g.levels[idx]
+
+    g.maze = append([]string(nil), lvl.Maze...)
+    g.ghosts = nil
+    g.numDots = 0
+    g.playerDir, g.nextDir = "", ""
+    g.playerWait = 0
+    g.ghostsEaten = 0
+
+    g.speeds = g.cfg.Speeds.withDefaults()
+    if lvl.GhostSpeed > 0 {
+        g.speeds.Ghost = lvl.GhostSpeed
+    }
+    g.pillDuration = int(g.cfg.PillDurationSecs) * TickRate
+    if lvl.PillDurationSecs > 0 {
+        g.pillDuration = int(lvl.PillDurationSecs) * TickRate
+    }
+    g.modes = newModeScheduler(modePhases(g.cfg.ModeSchedule, g.level))
+
+    // traverse each character of the maze
+    for row, line := range g.maze {
+        for col, char := range line {
+            switch char {
+            case 'P':
+                g.player = sprite{row, col, row, col}
+            case 'G':
+                g.ghosts = append(g.ghosts, &ghost{
+                    position:    sprite{row, col, row, col},
+                    status:      GhostStatusNormal,
+                    personality: personality(len(g.ghosts)) % numPersonalities,
+                })
+            case '.':
+                g.numDots++
+            }
+        }
+    }
+
+    g.house = g.findHouse()
+    g.houseDots, g.houseTimer, g.released = 0, 0, 0
+    for _, ghost := range g.ghosts {
+        if g.house != nil && g.house.inside(Position{ghost.position.startRow, ghost.position.startCol}) {
+            ghost.house = houseWaiting
+        }
+    }
+
+    g.fruit = fruitForLevel(lvl, g.level)
+    g.fruitSpot = g.findFruitSpot()
+    g.fruitTicks, g.fruitsSpawned, g.levelDots = 0, 0, 0
+}
+
+// levelCleared starts the intermission before the next level, or ends the
+// game once the last level has been cleared
+func (g *Game) levelCleared() {
+    g.emit(LevelCleared{
+        EventInfo: g.at(g.player.row, g.player.col),
+        Level:     g.level,
+    })
+    if g.level >= len(g.levels) {
+        g.won = true
+        return
+    }
+    g.intermission = intermissionTicks
+}
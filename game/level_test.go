@@ -0,0 +1,140 @@
+package game
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestLevelProgression(t *testing.T) {
+    levels := []Level{
+        {Maze: []string{"#####", "#P. #", "#####"}},
+        {Maze: []string{"#####", "# .P#", "#####"}, GhostSpeed: 2},
+    }
+    g := New(Config{Speeds: Speeds{Player: 1}}, levels, 1)
+
+    g.Step("RIGHT")
+    if s := g.Snapshot(); !s.Intermission || s.Level != 1 || s.Score != 1 {
+        t.Fatalf("after clearing level 1: intermission %v, level %d, score %d, want true, 1, 1", s.Intermission, s.Level, s.Score)
+    }
+
+    // nothing moves during the intermission, not even for a key
+    for i := 0; i < intermissionTicks-1; i++ {
+        g.Step("LEFT")
+        if s := g.Snapshot(); !s.Intermission || s.Player != (Position{1, 2}) {
+            t.Fatalf("tick %d of the intermission: intermission %v, player %v", i+2, s.Intermission, s.Player)
+        }
+    }
+
+    g.Step("")
+    s := g.Snapshot()
+    if s.Intermission || s.Level != 2 {
+        t.Fatalf("after the intermission: intermission %v, level %d, want false, 2", s.Intermission, s.Level)
+    }
+    if s.Player != (Position{1, 3}) || s.DotsLeft != 1 || s.Score != 1 {
+        t.Errorf("level 2 started with player %v, %d dots and score %d, want 1,3, 1 dot and score 1", s.Player, s.DotsLeft, s.Score)
+    }
+    if g.speeds.Ghost != 2 {
+        t.Errorf("ghost speed on level 2 = %d, want 2", g.speeds.Ghost)
+    }
+
+    g.Step("LEFT")
+    if !g.IsOver() || g.Outcome() != OutcomeWon {
+        t.Errorf("outcome after clearing the last level = %v, want %v", g.Outcome(), OutcomeWon)
+    }
+}
+
+func TestLoadLevels(t *testing.T) {
+    dir := t.TempDir()
+    write := func(name, content string) {
+        t.Helper()
+        if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+            t.Fatal(err)
+        }
+    }
+    write("one.txt", "######\n#P. G#\n######\n")
+    write("levels.json", `[{"maze": "one.txt", "fruit": "apple"}, {"maze": "one.txt", "ghost_speed": 3}]`)
+    write("nofruit.json", `[{"maze": "one.txt", "fruit": "banana"}]`)
+    write("empty.json", `[]`)
+
+    levels, err := LoadLevels(filepath.Join(dir, "levels.json"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(levels) != 2 || levels[0].Fruit != "apple" || levels[1].GhostSpeed != 3 {
+        t.Errorf("levels = %+v", levels)
+    }
+    if got := strings.Join(levels[1].Maze, "\n"); got != "######\n#P. G#\n######" {
+        t.Errorf("maze of level 2 = %q", got)
+    }
+
+    if _, err := LoadLevels(filepath.Join(dir, "nofruit.json")); err == nil || !strings.Contains(err.Error(), `level 1: unknown fruit "banana"`) {
+        t.Errorf("unknown fruit: error = %v", err)
+    }
+    if _, err := LoadLevels(filepath.Join(dir, "empty.json")); err == nil || !strings.Contains(err.Error(), "no levels") {
+        t.Errorf("no levels: error = %v", err)
+    }
+}
+
+func TestHashLevels(t *testing.T) {
+    base := []Level{
+        {Maze: []string{"#####", "#P. #", "#####"}},
+        {Maze: []string{"#####", "# .P#", "#####"}},
+    }
+    hash := HashLevels(base)
+
+    // clone returns a deep copy of base
+    clone := func() []Level {
+        levels := make([]Level, len(base))
+        copy(levels, base)
+        for i := range levels {
+            levels[i].Maze = append([]string(nil), base[i].Maze...)
+        }
+        return levels
+    }
+
+    same := clone()
+    // the file a maze came from does not matter, only what is in it
+    same[0].MazeFile = "elsewhere.txt"
+    if got := HashLevels(same); got != hash {
+        t.Errorf("hash of the same levels changed: %s, want %s", got, hash)
+    }
+
+    changes := map[string]func(levels []Level) []Level{
+        "maze": func(levels []Level) []Level {
+            levels[1].Maze[1] = "#. P#"
+            return levels
+        },
+        "ghost speed": func(levels []Level) []Level {
+            levels[0].GhostSpeed = 2
+            return levels
+        },
+        "pill duration": func(levels []Level) []Level {
+            levels[1].PillDurationSecs = 3
+            return levels
+        },
+        "fruit": func(levels []Level) []Level {
+            levels[0].Fruit = "key"
+            return levels
+        },
+        "order": func(levels []Level) []Level {
+            levels[0], levels[1] = levels[1], levels[0]
+            return levels
+        },
+        "number of levels": func(levels []Level) []Level {
+            return levels[:1]
+        },
+        // the rows of one maze must not run into the next one
+        "split between mazes": func(levels []Level) []Level {
+            levels[0].Maze = append(levels[0].Maze, levels[1].Maze[0])
+            levels[1].Maze = levels[1].Maze[1:]
+            return levels
+        },
+    }
+    for name, change := range changes {
+        if got := HashLevels(change(clone())); got == hash {
+            t.Errorf("changing the %s kept the hash", name)
+        }
+    }
+}
@@ -0,0 +1,33 @@
+package game
+
+import (
+    "bufio"
+    "os"
+)
+
+// LoadMaze reads a maze file, one row per line, and validates it. If the
+// maze is not playable the error is a *ValidationError listing every
+// problem found.
+func LoadMaze(file string) ([]string, error) {
+    f, err := os.Open(file)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    var maze []string
+    scanner := bufio.NewScanner(f)
+    for scanner.Scan() {
+        line := scanner.Text()
+        maze = append(maze, line)
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, err
+    }
+
+    if errs := ValidateMaze(maze); len(errs) > 0 {
+        return nil, &ValidationError{File: file, Errors: errs}
+    }
+
+    return maze, nil
+}
@@ -0,0 +1,102 @@
+package game
+
+// Mode is the global behaviour shared by all ghosts
+type Mode string
+
+const (
+    ModeScatter    Mode = "Scatter"
+    ModeChase      Mode = "Chase"
+    ModeFrightened Mode = "Frightened"
+)
+
+// ModeSchedule is the scatter/chase timetable used from a given level on.
+// Phases alternate between scatter and chase, starting with scatter; once
+// they run out the ghosts chase forever.
+type ModeSchedule struct {
+    FromLevel  int       `json:"from_level"`
+    PhasesSecs []float64 `json:"phases_secs"`
+}
+
+// defaultModeSchedules is the arcade timetable
+var defaultModeSchedules = []ModeSchedule{
+    {FromLevel: 1, PhasesSecs: []float64{7, 20, 7, 20, 5, 20, 5}},
+    {FromLevel: 2, PhasesSecs: []float64{7, 20, 7, 20, 5, 1033, 1.0 / 60}},
+    {FromLevel: 5, PhasesSecs: []float64{5, 20, 5, 20, 5, 1037, 1.0 / 60}},
+}
+
+// modePhases returns the phase durations in ticks for a level, picking the
+// schedule with the highest starting level that applies
+func modePhases(schedules []ModeSchedule, level int) []int {
+    if len(schedules) == 0 {
+        schedules = defaultModeSchedules
+    }
+
+    var best *ModeSchedule
+    for i := range schedules {
+        s := &schedules[i]
+        if s.FromLevel <= level && (best == nil || s.FromLevel > best.FromLevel) {
+            best = s
+        }
+    }
+    if best == nil {
+        return nil
+    }
+
+    phases := make([]int, len(best.PhasesSecs))
+    for i, secs := range best.PhasesSecs {
+        phases[i] = int(secs*TickRate + 0.5)
+        if phases[i] < 1 {
+            phases[i] = 1
+        }
+    }
+    return phases
+}
+
+// modeScheduler runs the scatter/chase clock. Frightened mode pauses the
+// clock, which resumes where it left off once the pill wears off.
+type modeScheduler struct {
+    phases     []int
+    phase      int
+    elapsed    int
+    frightened int
+}
+
+func newModeScheduler(phases []int) *modeScheduler {
+    return &modeScheduler{phases: phases}
+}
+
+func (m *modeScheduler) mode() Mode {
+    if m.frightened > 0 {
+        return ModeFrightened
+    }
+    if m.phase%2 == 0 && m.phase < len(m.phases) {
+        return ModeScatter
+    }
+    return ModeChase
+}
+
+// advance moves the clock forward by one tick and reports whether the mode
+// changed
+func (m *modeScheduler) advance() bool {
+    before := m.mode()
+
+    if m.frightened > 0 {
+        m.frightened--
+    } else {
+        m.elapsed++
+        if m.phase < len(m.phases) && m.elapsed >= m.phases[m.phase] {
+            m.elapsed = 0
+            m.phase++
+        }
+    }
+
+    return m.mode() != before
+}
+
+// frighten starts (or restarts) frightened mode for the given number of
+// ticks and reports whether the mode changed
+func (m *modeScheduler) frighten(ticks int) bool {
+    before := m.mode()
+    m.frightened = ticks
+    return m.mode() != before
+}
@@ -0,0 +1,174 @@
+package game
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestModePhases(t *testing.T) {
+    tests := []struct {
+        name      string
+        schedules []ModeSchedule
+        level     int
+        want      []int
+    }{
+        {name: "arcade level 1", level: 1, want: []int{140, 400, 140, 400, 100, 400, 100}},
+        {name: "arcade level 3", level: 3, want: []int{140, 400, 140, 400, 100, 20660, 1}},
+        {name: "arcade level 9", level: 9, want: []int{100, 400, 100, 400, 100, 20740, 1}},
+        {
+            name:      "configured",
+            schedules: []ModeSchedule{{FromLevel: 1, PhasesSecs: []float64{1, 2.5}}, {FromLevel: 3, PhasesSecs: []float64{3}}},
+            level:     2,
+            want:      []int{20, 50},
+        },
+        {
+            name:      "no schedule applies",
+            schedules: []ModeSchedule{{FromLevel: 2, PhasesSecs: []float64{1}}},
+            level:     1,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := modePhases(tt.schedules, tt.level); !reflect.DeepEqual(got, tt.want) {
+                t.Errorf("modePhases() = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestModeSchedulerTimetable(t *testing.T) {
+    m := newModeScheduler([]int{2, 3})
+
+    want := []Mode{ModeScatter, ModeChase, ModeChase, ModeChase, ModeChase, ModeChase}
+    for i, mode := range want {
+        changed := m.advance()
+        if m.mode() != mode {
+            t.Fatalf("mode after %d ticks = %v, want %v", i+1, m.mode(), mode)
+        }
+        // scatter turns to chase after 2 ticks, and chase lasts forever
+        // once the phases run out
+        if wantChanged := i == 1; changed != wantChanged {
+            t.Errorf("advance() on tick %d = %v, want %v", i+1, changed, wantChanged)
+        }
+    }
+}
+
+func TestModeSchedulerPausesWhileFrightened(t *testing.T) {
+    m := newModeScheduler([]int{3, 3})
+    m.advance()
+    m.advance()
+
+    if !m.frighten(5) {
+        t.Errorf("frighten() = false, want true")
+    }
+    for i := 0; i < 4; i++ {
+        if m.advance() {
+            t.Fatalf("advance() reported a change after %d frightened ticks", i+1)
+        }
+    }
+    if !m.advance() {
+        t.Errorf("advance() = false when the pill wore off, want true")
+    }
+
+    // the clock picks up where it stopped: one scatter tick is left
+    if m.mode() != ModeScatter {
+        t.Fatalf("mode after the pill = %v, want %v", m.mode(), ModeScatter)
+    }
+    if !m.advance() || m.mode() != ModeChase {
+        t.Errorf("mode one tick after the pill = %v, want %v", m.mode(), ModeChase)
+    }
+}
+
+func TestModeSchedulerPillWhileFrightened(t *testing.T) {
+    m := newModeScheduler([]int{100})
+    m.frighten(5)
+    m.advance()
+    m.advance()
+
+    if m.frighten(5) {
+        t.Errorf("frighten() while frightened reported a change")
+    }
+    // the second pill restarts the timer
+    for i := 0; i < 4; i++ {
+        m.advance()
+    }
+    if m.mode() != ModeFrightened {
+        t.Errorf("mode 4 ticks after the second pill = %v, want %v", m.mode(), ModeFrightened)
+    }
+}
+
+func TestApplyModeReversesGhosts(t *testing.T) {
+    tests := []struct {
+        name     string
+        from, to Mode
+        house    houseState
+        wantDir  string
+    }{
+        {name: "scatter to chase", from: ModeScatter, to: ModeChase, wantDir: "RIGHT"},
+        {name: "to frightened", from: ModeChase, to: ModeFrightened, wantDir: "RIGHT"},
+        {name: "end of frightened", from: ModeFrightened, to: ModeChase, wantDir: "LEFT"},
+        {name: "in the ghost house", from: ModeScatter, to: ModeChase, house: houseLeaving, wantDir: "LEFT"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            g := New(Config{}, []Level{{Maze: corridor}}, 1)
+            g.modes = newModeScheduler(nil)
+            if tt.to == ModeFrightened {
+                g.modes.frighten(10)
+            }
+            gh := &ghost{position: sprite{1, 4, 1, 4}, status: GhostStatusNormal, dir: "LEFT", house: tt.house}
+            g.ghosts = []*ghost{gh}
+
+            g.applyMode(tt.from)
+            if gh.dir != tt.wantDir {
+                t.Errorf("dir = %v, want %v", gh.dir, tt.wantDir)
+            }
+        })
+    }
+}
+
+// pills is a corridor with two power pills side by side, and a dot far away
+// so that the level is not cleared
+var pills = []string{
+    "##########",
+    "#XX      #",
+    "#######.##",
+    "##########",
+}
+
+func TestPillDuringFrightenedAfterGhostRespawned(t *testing.T) {
+    cfg := Config{
+        PillDurationSecs: 10,
+        Speeds:           Speeds{Player: 1, Ghost: 1, GhostFrightened: 1, GhostEyes: 1},
+    }
+    g := New(cfg, []Level{{Maze: pills}}, 1)
+
+    g.player = sprite{1, 3, 1, 3}
+    g.playerDir = "LEFT"
+    // keep the ghosts still, away from the player
+    respawned := &ghost{position: sprite{1, 7, 1, 7}, status: GhostStatusNormal, wait: 100}
+    eyes := &ghost{position: sprite{1, 8, 1, 8}, status: GhostStatusNormal, wait: 100}
+    g.ghosts = []*ghost{respawned, eyes}
+
+    // the first pill frightens both ghosts
+    g.Step("")
+    if respawned.status != GhostStatusBlue || eyes.status != GhostStatusBlue {
+        t.Fatalf("statuses after the first pill = %v, %v, want both %v", respawned.status, eyes.status, GhostStatusBlue)
+    }
+
+    // one ghost was eaten and has come back out of the house, the other is
+    // still on its way home
+    respawned.status = GhostStatusNormal
+    eyes.status = GhostStatusEyes
+
+    g.Step("")
+    if g.modes.mode() != ModeFrightened {
+        t.Fatalf("mode after the second pill = %v, want %v", g.modes.mode(), ModeFrightened)
+    }
+    if respawned.status != GhostStatusBlue {
+        t.Errorf("respawned ghost is %v after the second pill, want %v", respawned.status, GhostStatusBlue)
+    }
+    if eyes.status != GhostStatusEyes {
+        t.Errorf("eaten ghost is %v after the second pill, want %v", eyes.status, GhostStatusEyes)
+    }
+}
@@ -0,0 +1,41 @@
+package game
+
+var directions = []string{"UP", "LEFT", "DOWN", "RIGHT"}
+
+// nextStepTowards returns the first direction of a shortest path from one
+// cell to another, or an empty string when the target is unreachable or
+// already reached. The search uses moveThrough, so tunnels, walls and the
+// ghost house door behave exactly as they do for regular movement.
+func (g *Game) nextStepTowards(from, to Position, throughDoor bool) string {
+    if from == to {
+        return ""
+    }
+
+    // breadth-first search, remembering the first move taken on each path
+    firstDir := map[Position]string{from: ""}
+    queue := []Position{from}
+    for len(queue) > 0 {
+        cur := queue[0]
+        queue = queue[1:]
+
+        for _, dir := range directions {
+            row, col := g.moveThrough(cur.Row, cur.Col, dir, throughDoor)
+            next := Position{row, col}
+            if _, seen := firstDir[next]; seen {
+                continue
+            }
+
+            if cur == from {
+                firstDir[next] = dir
+            } else {
+                firstDir[next] = firstDir[cur]
+            }
+            if next == to {
+                return firstDir[next]
+            }
+            queue = append(queue, next)
+        }
+    }
+
+    return ""
+}
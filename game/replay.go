@@ -0,0 +1,88 @@
+package game
+
+import (
+    "encoding/json"
+    "os"
+    "sort"
+)
+
+// InputEvent is a single input together with the tick it was applied on
+type InputEvent struct {
+    Tick  int    `json:"tick"`
+    Input string `json:"input"`
+}
+
+// Replay is everything needed to re-run a recorded game: the seed, the
+// levels and configuration it was played with and every input received.
+// MazeHash covers every level, so a replay is only re-run on the levels it
+// was recorded on.
+// The final tick and score are kept so that a replay can be checked
+// against the original run.
+type Replay struct {
+    Seed       int64        `json:"seed"`
+    LevelsFile string       `json:"levels_file,omitempty"`
+    MazeFile   string       `json:"maze_file"`
+    MazeHash   string       `json:"maze_hash"`
+    Config     Config       `json:"config"`
+    Inputs     []InputEvent `json:"inputs"`
+    FinalTick  int          `json:"final_tick"`
+    FinalScore int          `json:"final_score"`
+}
+
+// Record appends an input applied on the given tick. Empty inputs are not
+// recorded.
+func (r *Replay) Record(tick int, input string) {
+    if input == "" {
+        return
+    }
+    r.Inputs = append(r.Inputs, InputEvent{tick, input})
+}
+
+// Finish stores the final state of the recorded game
+func (r *Replay) Finish(g *Game) {
+    r.FinalTick = g.Tick()
+    r.FinalScore = g.Snapshot().Score
+}
+
+// Input returns the input recorded for the given tick, or an empty string
+func (r *Replay) Input(tick int) string {
+    return inputAt(r.Inputs, tick)
+}
+
+// SaveReplay writes a replay as JSON
+func SaveReplay(file string, r *Replay) error {
+    f, err := os.Create(file)
+    if err != nil {
+        return err
+    }
+
+    encoder := json.NewEncoder(f)
+    encoder.SetIndent("", "  ")
+    if err := encoder.Encode(r); err != nil {
+        f.Close()
+        return err
+    }
+
+    return f.Close()
+}
+
+// LoadReplay reads a replay written by SaveReplay
+func LoadReplay(file string) (*Replay, error) {
+    f, err := os.Open(file)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    var r Replay
+    decoder := json.NewDecoder(f)
+    err = decoder.Decode(&r)
+    if err != nil {
+        return nil, err
+    }
+
+    sort.SliceStable(r.Inputs, func(i, j int) bool {
+        return r.Inputs[i].Tick < r.Inputs[j].Tick
+    })
+    return &r, nil
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+    "math/rand"
+    "path/filepath"
+    "reflect"
+    "testing"
+)
+
+// small is a maze with ghosts roaming freely and power pills, so that a
+// game on it goes through frightened mode and its random moves
+var small = []string{
+    "#########",
+    "#X.....G#",
+    "#.##.##.#",
+    "#...P...#",
+    "#.##.##.#",
+    "#G.....X#",
+    "#########",
+}
+
+// play runs a game until it ends or maxTicks is reached, feeding it the
+// input returned for each tick, and returns every event sent
+func play(g *Game, inputAt func(tick int) string, record *Replay) []Event {
+    var events []Event
+    g.Subscribe(func(e Event) {
+        events = append(events, e)
+    })
+
+    const maxTicks = 5000
+    for !g.IsOver() && g.Tick() < maxTicks {
+        inp := inputAt(g.Tick() + 1)
+        g.Step(inp)
+        if record != nil {
+            record.Record(g.Tick(), inp)
+        }
+    }
+    return events
+}
+
+func TestReplayMatchesRecordedGame(t *testing.T) {
+    const seed = 42
+    levels := []Level{{Maze: small}}
+    cfg := Config{PillDurationSecs: 3}
+
+    // a player turning at random every few ticks
+    keys := rand.New(rand.NewSource(7))
+    dirs := []string{"UP", "DOWN", "LEFT", "RIGHT"}
+    randomInput := func(tick int) string {
+        if keys.Intn(5) == 0 {
+            return dirs[keys.Intn(len(dirs))]
+        }
+        return ""
+    }
+
+    original := New(cfg, levels, seed)
+    rec := &Replay{Seed: seed, MazeHash: HashLevels(levels), Config: cfg}
+    want := play(original, randomInput, rec)
+    rec.Finish(original)
+    if len(rec.Inputs) == 0 {
+        t.Fatal("no inputs were recorded")
+    }
+
+    file := filepath.Join(t.TempDir(), "replay.json")
+    if err := SaveReplay(file, rec); err != nil {
+        t.Fatal(err)
+    }
+    loaded, err := LoadReplay(file)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    replayed := New(loaded.Config, levels, loaded.Seed)
+    got := play(replayed, loaded.Input, nil)
+
+    if replayed.Tick() != loaded.FinalTick {
+        t.Errorf("replay ended on tick %d, want %d", replayed.Tick(), loaded.FinalTick)
+    }
+    if s := replayed.Snapshot(); s.Score != loaded.FinalScore {
+        t.Errorf("replay scored %d, want %d", s.Score, loaded.FinalScore)
+    }
+    if got, want := replayed.Summary(), original.Summary(); got != want {
+        t.Errorf("replay summary = %+v, want %+v", got, want)
+    }
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("replay sent %d events, want the %d events of the recorded game", len(got), len(want))
+        for i := 0; i < len(got) && i < len(want); i++ {
+            if !reflect.DeepEqual(got[i], want[i]) {
+                t.Errorf("first difference at event %d: %#v, want %#v", i, got[i], want[i])
+                break
+            }
+        }
+    }
+}
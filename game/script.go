@@ -0,0 +1,90 @@
+package game
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// Script is a list of inputs to feed to a game without a keyboard
+type Script struct {
+    Inputs []InputEvent
+}
+
+var scriptKeys = map[string]bool{
+    "UP":    true,
+    "DOWN":  true,
+    "LEFT":  true,
+    "RIGHT": true,
+    "ESC":   true,
+}
+
+// ParseScript reads a script, one input per line. A line is either a bare
+// key, applied on the tick after the previous line; a tick number and a
+// key, e.g. "40 LEFT"; or an offset from the previous line and a key, e.g.
+// "+8 DOWN". Blank lines and lines starting with # are ignored.
+func ParseScript(r io.Reader) (*Script, error) {
+    var s Script
+    tick := 0
+    lineNo := 0
+
+    scanner := bufio.NewScanner(r)
+    for scanner.Scan() {
+        lineNo++
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+
+        fields := strings.Fields(line)
+        switch len(fields) {
+        case 1:
+            tick++
+        case 2:
+            n, err := strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+            if err != nil || n < 0 {
+                return nil, fmt.Errorf("line %d: invalid tick %q", lineNo, fields[0])
+            }
+            if strings.HasPrefix(fields[0], "+") {
+                tick += n
+            } else {
+                tick = n
+            }
+        default:
+            return nil, fmt.Errorf("line %d: expected [tick] KEY, got %q", lineNo, line)
+        }
+
+        key := strings.ToUpper(fields[len(fields)-1])
+        if !scriptKeys[key] {
+            return nil, fmt.Errorf("line %d: unknown key %q", lineNo, fields[len(fields)-1])
+        }
+        s.Inputs = append(s.Inputs, InputEvent{tick, key})
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, err
+    }
+
+    sort.SliceStable(s.Inputs, func(i, j int) bool {
+        return s.Inputs[i].Tick < s.Inputs[j].Tick
+    })
+    return &s, nil
+}
+
+// Input returns the input scripted for the given tick, or an empty string
+func (s *Script) Input(tick int) string {
+    return inputAt(s.Inputs, tick)
+}
+
+// inputAt looks up the input for a tick in a list sorted by tick
+func inputAt(inputs []InputEvent, tick int) string {
+    i := sort.Search(len(inputs), func(i int) bool {
+        return inputs[i].Tick >= tick
+    })
+    if i < len(inputs) && inputs[i].Tick == tick {
+        return inputs[i].Input
+    }
+    return ""
+}
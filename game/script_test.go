@@ -0,0 +1,65 @@
+package game
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestParseScript(t *testing.T) {
+    script := `# start by going left
+LEFT
+up
+
+40 RIGHT
++8 DOWN
+  +0 esc
+10 UP
+`
+    s, err := ParseScript(strings.NewReader(script))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    want := []InputEvent{
+        {1, "LEFT"},
+        {2, "UP"},
+        {10, "UP"},
+        {40, "RIGHT"},
+        {48, "DOWN"},
+        {48, "ESC"},
+    }
+    if !reflect.DeepEqual(s.Inputs, want) {
+        t.Errorf("Inputs = %v, want %v", s.Inputs, want)
+    }
+
+    if got := s.Input(40); got != "RIGHT" {
+        t.Errorf("Input(40) = %q, want RIGHT", got)
+    }
+    if got := s.Input(41); got != "" {
+        t.Errorf("Input(41) = %q, want none", got)
+    }
+}
+
+func TestParseScriptErrors(t *testing.T) {
+    tests := []struct {
+        name   string
+        script string
+        want   string
+    }{
+        {"unknown key", "UP\nJUMP\n", `line 2: unknown key "JUMP"`},
+        {"invalid tick", "UP\n\n# comment\nsoon LEFT\n", `line 4: invalid tick "soon"`},
+        {"negative tick", "-3 LEFT\n", `line 1: invalid tick "-3"`},
+        {"invalid offset", "+x LEFT\n", `line 1: invalid tick "+x"`},
+        {"too many fields", "1 2 LEFT\n", `line 1: expected [tick] KEY, got "1 2 LEFT"`},
+        {"unknown key after tick", "5 PAUSE\n", `line 1: unknown key "PAUSE"`},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := ParseScript(strings.NewReader(tt.script))
+            if err == nil || err.Error() != tt.want {
+                t.Errorf("ParseScript() error = %v, want %s", err, tt.want)
+            }
+        })
+    }
+}
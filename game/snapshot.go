@@ -0,0 +1,124 @@
+package game
+
+// Position is a cell in the maze
+type Position struct {
+    Row int
+    Col int
+}
+
+// GhostView is the visible state of a single ghost
+type GhostView struct {
+    Position
+    Status GhostStatus
+}
+
+// Snapshot is a copy of everything a front end needs to draw a frame. It
+// does not share memory with the game, so it stays valid after further
+// calls to Step.
+type Snapshot struct {
+    Maze   []string
+    Player Position
+    Ghosts []GhostView
+    // Fruit is the bonus fruit on the maze, if any
+    Fruit *FruitView
+    // Collected lists the fruits eaten so far, oldest first
+    Collected []string
+    Score     int
+    Lives     int
+    DotsLeft  int
+    Mode      Mode
+    Tick      int
+    Level     int
+    // Dying is set from the moment the player is caught until the player
+    // and the ghosts are put back at their start
+    Dying bool
+    // DeathAnimation is how far the death animation has got, from 0 to 1.
+    // It is 0 during the freeze before the animation starts.
+    DeathAnimation float64
+    // Ready is set during the countdown before play resumes
+    Ready bool
+    // Intermission is set between two levels
+    Intermission bool
+    // ExtraLife is set for a short while after an extra life was granted
+    ExtraLife bool
+}
+
+// Snapshot returns the current state of the game
+func (g *Game) Snapshot() Snapshot {
+    s := Snapshot{
+        Maze:     append([]string(nil), g.maze...),
+        Player:   Position{g.player.row, g.player.col},
+        Ghosts:   make([]GhostView, 0, len(g.ghosts)),
+        Score:    g.score,
+        Lives:    g.lives,
+        DotsLeft: g.numDots,
+        Mode:     g.modes.mode(),
+        Tick:     g.tick,
+        Level:    g.level,
+        Dying:    g.deathTicks > 0,
+        Ready:    g.readyTicks > 0,
+
+        DeathAnimation: g.deathAnimation(),
+        Intermission:   g.intermission > 0,
+        ExtraLife:      g.extraLifeFlash > 0,
+    }
+
+    if g.fruitTicks > 0 {
+        s.Fruit = &FruitView{Position: g.fruitSpot, Name: g.fruit}
+    }
+    s.Collected = append(s.Collected, g.collected...)
+
+    for _, ghost := range g.ghosts {
+        s.Ghosts = append(s.Ghosts, GhostView{
+            Position: Position{ghost.position.row, ghost.position.col},
+            Status:   ghost.status,
+        })
+    }
+
+    return s
+}
+
+// Outcome describes how a game ended
+type Outcome string
+
+const (
+    OutcomeRunning Outcome = "running"
+    OutcomeWon     Outcome = "won"
+    OutcomeLost    Outcome = "lost"
+)
+
+// Outcome returns the result of the game so far
+func (g *Game) Outcome() Outcome {
+    switch {
+    case g.lives <= 0:
+        return OutcomeLost
+    case g.won:
+        return OutcomeWon
+    default:
+        return OutcomeRunning
+    }
+}
+
+// Summary is a short machine readable report of a game
+type Summary struct {
+    Seed     int64   `json:"seed"`
+    Ticks    int     `json:"ticks"`
+    Score    int     `json:"score"`
+    Lives    int     `json:"lives"`
+    Level    int     `json:"level"`
+    DotsLeft int     `json:"dots_left"`
+    Outcome  Outcome `json:"outcome"`
+}
+
+// Summary returns a report of the game so far
+func (g *Game) Summary() Summary {
+    return Summary{
+        Seed:     g.seed,
+        Ticks:    g.tick,
+        Score:    g.score,
+        Lives:    g.lives,
+        Level:    g.level,
+        DotsLeft: g.numDots,
+        Outcome:  g.Outcome(),
+    }
+}
@@ -0,0 +1,198 @@
+package game
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+)
+
+// knownGlyphs are the characters a maze may be drawn with
+const knownGlyphs = "#.XPG- "
+
+// MazeError is a single problem found in a maze. Line and Col are 1-based;
+// a zero Col means the problem concerns the whole line, and a zero Line the
+// whole maze.
+type MazeError struct {
+    Line int
+    Col  int
+    Msg  string
+}
+
+func (e MazeError) Error() string {
+    switch {
+    case e.Line == 0:
+        return e.Msg
+    case e.Col == 0:
+        return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+    default:
+        return fmt.Sprintf("line %d, col %d: %s", e.Line, e.Col, e.Msg)
+    }
+}
+
+// ValidationError lists every problem found in a maze file
+type ValidationError struct {
+    File   string
+    Errors []MazeError
+}
+
+func (e *ValidationError) Error() string {
+    var b strings.Builder
+    fmt.Fprintf(&b, "%s is not a valid maze:", e.File)
+    for _, err := range e.Errors {
+        fmt.Fprintf(&b, "\n  %s", err)
+    }
+    return b.String()
+}
+
+// ValidateMaze checks that a maze is playable: it must be rectangular, use
+// known glyphs only, have exactly one player start and at least one ghost,
+// every dot and pill must be reachable from the player start, and every
+// tunnel must come out on the opposite edge. It returns all problems found.
+func ValidateMaze(maze []string) []MazeError {
+    if len(maze) == 0 {
+        return []MazeError{{Msg: "maze is empty"}}
+    }
+
+    var errs []MazeError
+    width := len(maze[0])
+    var player *Position
+    ghosts := 0
+
+    for row, line := range maze {
+        if len(line) != width {
+            errs = append(errs, MazeError{row + 1, 0,
+                fmt.Sprintf("line is %d characters wide, expected %d like line 1", len(line), width)})
+        }
+
+        for col, char := range line {
+            switch {
+            case !strings.ContainsRune(knownGlyphs, char):
+                errs = append(errs, MazeError{row + 1, col + 1, fmt.Sprintf("unknown glyph %q", char)})
+            case char == 'P' && player != nil:
+                errs = append(errs, MazeError{row + 1, col + 1,
+                    fmt.Sprintf("second player start, the first one is at line %d, col %d", player.Row+1, player.Col+1)})
+            case char == 'P':
+                player = &Position{row, col}
+            case char == 'G':
+                ghosts++
+            }
+        }
+    }
+
+    if player == nil {
+        errs = append(errs, MazeError{Msg: "no player start (P)"})
+    }
+    if ghosts == 0 {
+        errs = append(errs, MazeError{Msg: "no ghosts (G)"})
+    }
+
+    // the remaining checks need a grid, so they run on the padded maze
+    // even if the maze is ragged
+    grid := padMaze(maze)
+    errs = append(errs, checkTunnels(grid)...)
+    if player != nil {
+        errs = append(errs, checkReachable(grid, *player)...)
+    }
+
+    sort.SliceStable(errs, func(i, j int) bool {
+        if errs[i].Line != errs[j].Line {
+            return errs[i].Line < errs[j].Line
+        }
+        return errs[i].Col < errs[j].Col
+    })
+    return errs
+}
+
+// isOpen reports whether the player can stand on a tile
+func isOpen(char byte) bool {
+    return char != '#' && char != '-'
+}
+
+// checkTunnels makes sure that every opening on an edge of the maze has a
+// matching opening on the opposite edge to wrap around to
+func checkTunnels(grid []string) []MazeError {
+    var errs []MazeError
+    last := len(grid[0]) - 1
+    if last < 0 {
+        return nil
+    }
+
+    for row, line := range grid {
+        if isOpen(line[0]) != isOpen(line[last]) {
+            col := 1
+            if !isOpen(line[0]) {
+                col = last + 1
+            }
+            errs = append(errs, MazeError{row + 1, col, "tunnel has no exit on the opposite side of the maze"})
+        }
+    }
+
+    bottom := len(grid) - 1
+    for col := range grid[0] {
+        if isOpen(grid[0][col]) != isOpen(grid[bottom][col]) {
+            row := 1
+            if !isOpen(grid[0][col]) {
+                row = bottom + 1
+            }
+            errs = append(errs, MazeError{row, col + 1, "tunnel has no exit on the opposite side of the maze"})
+        }
+    }
+
+    return errs
+}
+
+// reachable returns every tile the player can get to from start
+func reachable(grid []string, start Position) map[Position]bool {
+    height, width := len(grid), len(grid[0])
+    seen := map[Position]bool{start: true}
+    queue := []Position{start}
+    for len(queue) > 0 {
+        cur := queue[0]
+        queue = queue[1:]
+
+        for _, dir := range directions {
+            dr, dc := dirVector(dir)
+            next := Position{(cur.Row + dr + height) % height, (cur.Col + dc + width) % width}
+            if seen[next] || !isOpen(grid[next.Row][next.Col]) {
+                continue
+            }
+            seen[next] = true
+            queue = append(queue, next)
+        }
+    }
+    return seen
+}
+
+// checkReachable flood fills the maze from the player start, wrapping
+// around the edges like the tunnels do, and reports every dot or pill the
+// player can never get to
+func checkReachable(grid []string, start Position) []MazeError {
+    seen := reachable(grid, start)
+
+    var errs []MazeError
+    for row, line := range grid {
+        for col := 0; col < len(line); col++ {
+            if (line[col] == '.' || line[col] == 'X') && !seen[Position{row, col}] {
+                errs = append(errs, MazeError{row + 1, col + 1, "unreachable from the player start"})
+            }
+        }
+    }
+    return errs
+}
+
+// padMaze returns a copy of the maze with every row padded to the same
+// width, so that a ragged maze can still be checked as a grid
+func padMaze(maze []string) []string {
+    width := 0
+    for _, line := range maze {
+        if len(line) > width {
+            width = len(line)
+        }
+    }
+
+    padded := make([]string, len(maze))
+    for i, line := range maze {
+        padded[i] = line + strings.Repeat(" ", width-len(line))
+    }
+    return padded
+}
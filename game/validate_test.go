@@ -0,0 +1,183 @@
+package game
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestValidateMaze(t *testing.T) {
+    const (
+        tunnel      = "tunnel has no exit on the opposite side of the maze"
+        unreachable = "unreachable from the player start"
+    )
+    tests := []struct {
+        name string
+        maze []string
+        want []MazeError
+    }{
+        {
+            name: "valid",
+            maze: []string{
+                "#######",
+                "#P.G.X#",
+                "#######",
+            },
+        },
+        {
+            name: "valid with tunnels",
+            maze: []string{
+                "###.###",
+                " P.G.X ",
+                "###.###",
+            },
+        },
+        {
+            name: "empty",
+            want: []MazeError{{0, 0, "maze is empty"}},
+        },
+        {
+            name: "ragged",
+            maze: []string{
+                "#######",
+                "#P.G.#",
+                "#######",
+            },
+            // the short line is padded with an open tile for the other
+            // checks, which leaves a tunnel without an exit
+            want: []MazeError{
+                {2, 0, "line is 6 characters wide, expected 7 like line 1"},
+                {2, 7, tunnel},
+            },
+        },
+        {
+            name: "no player",
+            maze: []string{
+                "#####",
+                "#.G.#",
+                "#####",
+            },
+            want: []MazeError{{0, 0, "no player start (P)"}},
+        },
+        {
+            name: "two players",
+            maze: []string{
+                "######",
+                "#P.PG#",
+                "######",
+            },
+            want: []MazeError{{2, 4, "second player start, the first one is at line 2, col 2"}},
+        },
+        {
+            name: "no ghosts",
+            maze: []string{
+                "#####",
+                "#P..#",
+                "#####",
+            },
+            want: []MazeError{{0, 0, "no ghosts (G)"}},
+        },
+        {
+            name: "unreachable dot and pill",
+            maze: []string{
+                "########",
+                "#P.G#.X#",
+                "########",
+            },
+            want: []MazeError{{2, 6, unreachable}, {2, 7, unreachable}},
+        },
+        {
+            name: "dot behind the ghost house door",
+            maze: []string{
+                "#######",
+                "#P.G-.#",
+                "#######",
+            },
+            want: []MazeError{{2, 6, unreachable}},
+        },
+        {
+            name: "tunnel on the right only",
+            maze: []string{
+                "#####",
+                "#P.G ",
+                "#####",
+            },
+            want: []MazeError{{2, 5, tunnel}},
+        },
+        {
+            name: "tunnel on the left only",
+            maze: []string{
+                "#####",
+                " P.G#",
+                "#####",
+            },
+            want: []MazeError{{2, 1, tunnel}},
+        },
+        {
+            name: "tunnel at the top only",
+            maze: []string{
+                "##.##",
+                "#P.G#",
+                "#####",
+            },
+            want: []MazeError{{1, 3, tunnel}},
+        },
+        {
+            name: "tunnel at the bottom only",
+            maze: []string{
+                "#####",
+                "#P.G#",
+                "##.##",
+            },
+            want: []MazeError{{3, 3, tunnel}},
+        },
+        {
+            name: "unknown glyph",
+            maze: []string{
+                "#####",
+                "#P?G#",
+                "#####",
+            },
+            want: []MazeError{{2, 3, `unknown glyph '?'`}},
+        },
+        {
+            name: "several problems, in reading order",
+            maze: []string{
+                "#####",
+                "#?..#",
+                "#.#.",
+                "#####",
+            },
+            want: []MazeError{
+                {0, 0, "no player start (P)"},
+                {0, 0, "no ghosts (G)"},
+                {2, 2, `unknown glyph '?'`},
+                {3, 0, "line is 4 characters wide, expected 5 like line 1"},
+                {3, 5, tunnel},
+            },
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := ValidateMaze(tt.maze)
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Errorf("ValidateMaze() =\n%v\nwant\n%v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestMazeErrorString(t *testing.T) {
+    tests := []struct {
+        err  MazeError
+        want string
+    }{
+        {MazeError{0, 0, "maze is empty"}, "maze is empty"},
+        {MazeError{3, 0, "too short"}, "line 3: too short"},
+        {MazeError{3, 7, "bad"}, "line 3, col 7: bad"},
+    }
+    for _, tt := range tests {
+        if got := tt.err.Error(); got != tt.want {
+            t.Errorf("Error() = %q, want %q", got, tt.want)
+        }
+    }
+}
@@ -2,4 +2,7 @@ module github.com/hd2yao/pac-man
 
 go 1.20
 
-require github.com/danicat/simpleansi v0.0.0-20200320095209-8cd0472eec8b
+require (
+	github.com/danicat/simpleansi v0.0.0-20200320095209-8cd0472eec8b
+	golang.org/x/sys v0.15.0
+)
@@ -0,0 +1,141 @@
+// Package highscore keeps the local table of best scores.
+package highscore
+
+import (
+    "encoding/json"
+    "errors"
+    "io/fs"
+    "os"
+    "path/filepath"
+    "sort"
+    "time"
+)
+
+// MaxEntries is the number of scores kept in the table
+const MaxEntries = 10
+
+// Entry is a single score in the table
+type Entry struct {
+    Initials string    `json:"initials"`
+    Score    int       `json:"score"`
+    Date     time.Time `json:"date"`
+    Level    int       `json:"level"`
+    Maze     string    `json:"maze"`
+}
+
+// Table holds the best scores, highest first
+type Table struct {
+    Entries []Entry `json:"entries"`
+}
+
+// DefaultPath returns the location of the table in the user's
+// configuration directory
+func DefaultPath() (string, error) {
+    dir, err := os.UserConfigDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "pacman", "highscores.json"), nil
+}
+
+// Load reads the table from file. A missing file is an empty table.
+func Load(file string) (*Table, error) {
+    t := &Table{}
+
+    f, err := os.Open(file)
+    if errors.Is(err, fs.ErrNotExist) {
+        return t, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    decoder := json.NewDecoder(f)
+    err = decoder.Decode(t)
+    if err != nil {
+        return nil, err
+    }
+
+    t.sort()
+    return t, nil
+}
+
+// Save writes the table to file. It writes to a temporary file first and
+// renames it over the old table, so a crash never leaves a half written
+// table behind.
+func (t *Table) Save(file string) error {
+    dir := filepath.Dir(file)
+    err := os.MkdirAll(dir, 0o755)
+    if err != nil {
+        return err
+    }
+
+    tmp, err := os.CreateTemp(dir, ".highscores-*.json")
+    if err != nil {
+        return err
+    }
+    defer os.Remove(tmp.Name()) // no-op once renamed
+
+    encoder := json.NewEncoder(tmp)
+    encoder.SetIndent("", "  ")
+    if err := encoder.Encode(t); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Sync(); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        return err
+    }
+
+    return os.Rename(tmp.Name(), file)
+}
+
+// High returns the best score in the table, or 0 if it is empty
+func (t *Table) High() int {
+    if len(t.Entries) == 0 {
+        return 0
+    }
+    return t.Entries[0].Score
+}
+
+// Qualifies reports whether a score would make it into the table
+func (t *Table) Qualifies(score int) bool {
+    if score <= 0 {
+        return false
+    }
+    return len(t.Entries) < MaxEntries || score > t.Entries[len(t.Entries)-1].Score
+}
+
+// Add inserts an entry and drops the lowest scores beyond MaxEntries. It
+// returns the entry's position in the table, or -1 if it did not make it.
+func (t *Table) Add(e Entry) int {
+    if !t.Qualifies(e.Score) {
+        return -1
+    }
+
+    // later entries go after earlier ones with the same score
+    pos := sort.Search(len(t.Entries), func(i int) bool {
+        return t.Entries[i].Score < e.Score
+    })
+    t.Entries = append(t.Entries, Entry{})
+    copy(t.Entries[pos+1:], t.Entries[pos:])
+    t.Entries[pos] = e
+
+    if len(t.Entries) > MaxEntries {
+        t.Entries = t.Entries[:MaxEntries]
+    }
+    return pos
+}
+
+func (t *Table) sort() {
+    sort.SliceStable(t.Entries, func(i, j int) bool {
+        return t.Entries[i].Score > t.Entries[j].Score
+    })
+    if len(t.Entries) > MaxEntries {
+        t.Entries = t.Entries[:MaxEntries]
+    }
+}
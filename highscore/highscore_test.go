@@ -0,0 +1,192 @@
+package highscore
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "testing"
+    "time"
+)
+
+func scores(t *Table) []int {
+    var s []int
+    for _, e := range t.Entries {
+        s = append(s, e.Score)
+    }
+    return s
+}
+
+func TestAddKeepsTopTen(t *testing.T) {
+    table := &Table{}
+    for score := 10; score <= 120; score += 10 {
+        table.Add(Entry{Initials: "AAA", Score: score})
+    }
+
+    want := []int{120, 110, 100, 90, 80, 70, 60, 50, 40, 30}
+    if got := scores(table); !reflect.DeepEqual(got, want) {
+        t.Fatalf("scores = %v, want %v", got, want)
+    }
+
+    if pos := table.Add(Entry{Score: 30}); pos != -1 {
+        t.Errorf("Add of a score tying the lowest of a full table = %d, want -1", pos)
+    }
+    if pos := table.Add(Entry{Score: 5}); pos != -1 {
+        t.Errorf("Add of a score below the table = %d, want -1", pos)
+    }
+    if pos := table.Add(Entry{Score: 35}); pos != 9 {
+        t.Errorf("Add(35) = %d, want 9", pos)
+    }
+    if pos := table.Add(Entry{Score: 1000}); pos != 0 {
+        t.Errorf("Add(1000) = %d, want 0", pos)
+    }
+    want = []int{1000, 120, 110, 100, 90, 80, 70, 60, 50, 40}
+    if got := scores(table); !reflect.DeepEqual(got, want) {
+        t.Errorf("scores = %v, want %v", got, want)
+    }
+    if table.High() != 1000 {
+        t.Errorf("High() = %d, want 1000", table.High())
+    }
+}
+
+func TestAddTies(t *testing.T) {
+    table := &Table{}
+    table.Add(Entry{Initials: "AAA", Score: 500})
+    table.Add(Entry{Initials: "BBB", Score: 900})
+    // the later of two equal scores goes below the earlier one
+    if pos := table.Add(Entry{Initials: "CCC", Score: 500}); pos != 2 {
+        t.Errorf("Add of a tie = %d, want 2", pos)
+    }
+
+    var got []string
+    for _, e := range table.Entries {
+        got = append(got, e.Initials)
+    }
+    if want := []string{"BBB", "AAA", "CCC"}; !reflect.DeepEqual(got, want) {
+        t.Errorf("initials = %v, want %v", got, want)
+    }
+}
+
+func TestQualifies(t *testing.T) {
+    table := &Table{}
+    if table.Qualifies(0) {
+        t.Errorf("a zero score qualifies")
+    }
+    if !table.Qualifies(1) {
+        t.Errorf("a positive score does not qualify for an empty table")
+    }
+    if table.High() != 0 {
+        t.Errorf("High() of an empty table = %d, want 0", table.High())
+    }
+}
+
+func TestSaveAndLoad(t *testing.T) {
+    dir := filepath.Join(t.TempDir(), "pacman")
+    file := filepath.Join(dir, "highscores.json")
+
+    table := &Table{}
+    date := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+    table.Add(Entry{Initials: "ABC", Score: 1200, Date: date, Level: 2, Maze: "maze01.txt"})
+    table.Add(Entry{Initials: "XYZ", Score: 300, Date: date, Level: 1, Maze: "maze01.txt"})
+
+    // the directory is created on the first save
+    if err := table.Save(file); err != nil {
+        t.Fatal(err)
+    }
+    loaded, err := Load(file)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !reflect.DeepEqual(loaded, table) {
+        t.Errorf("loaded %+v, want %+v", loaded, table)
+    }
+
+    // saving again replaces the table, and leaves no temporary file behind
+    table.Add(Entry{Initials: "NEW", Score: 5000, Date: date})
+    if err := table.Save(file); err != nil {
+        t.Fatal(err)
+    }
+    entries, err := os.ReadDir(dir)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(entries) != 1 || entries[0].Name() != "highscores.json" {
+        var names []string
+        for _, e := range entries {
+            names = append(names, e.Name())
+        }
+        t.Errorf("files after saving = %v, want only highscores.json", names)
+    }
+    loaded, err = Load(file)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got, want := scores(loaded), []int{5000, 1200, 300}; !reflect.DeepEqual(got, want) {
+        t.Errorf("scores after saving again = %v, want %v", got, want)
+    }
+}
+
+func TestSaveFailureKeepsOldTable(t *testing.T) {
+    dir := t.TempDir()
+    file := filepath.Join(dir, "highscores.json")
+    table := &Table{Entries: []Entry{{Initials: "OLD", Score: 100}}}
+    if err := table.Save(file); err != nil {
+        t.Fatal(err)
+    }
+
+    // the rename fails when the target is a non-empty directory; the old
+    // table must survive and the temporary file must be cleaned up
+    if err := table.Save(dir); err == nil {
+        t.Fatal("Save over a directory succeeded")
+    }
+    entries, err := os.ReadDir(filepath.Dir(dir))
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, e := range entries {
+        if e.Name() != filepath.Base(dir) {
+            t.Errorf("left %s behind", e.Name())
+        }
+    }
+    loaded, err := Load(file)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := scores(loaded); !reflect.DeepEqual(got, []int{100}) {
+        t.Errorf("scores = %v, want [100]", got)
+    }
+}
+
+func TestLoad(t *testing.T) {
+    dir := t.TempDir()
+
+    table, err := Load(filepath.Join(dir, "missing.json"))
+    if err != nil || len(table.Entries) != 0 {
+        t.Errorf("Load of a missing file = %+v, %v, want an empty table", table, err)
+    }
+
+    // a hand edited file is sorted and cut down to the top ten
+    file := filepath.Join(dir, "edited.json")
+    data := `{"entries": [
+        {"score": 5}, {"score": 50}, {"score": 10}, {"score": 70}, {"score": 20}, {"score": 60},
+        {"score": 30}, {"score": 90}, {"score": 40}, {"score": 80}, {"score": 100}, {"score": 1}
+    ]}`
+    if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    table, err = Load(file)
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := []int{100, 90, 80, 70, 60, 50, 40, 30, 20, 10}
+    if got := scores(table); !reflect.DeepEqual(got, want) {
+        t.Errorf("scores = %v, want %v", got, want)
+    }
+
+    bad := filepath.Join(dir, "bad.json")
+    if err := os.WriteFile(bad, []byte("{"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := Load(bad); err == nil {
+        t.Errorf("Load of a broken file succeeded")
+    }
+}
@@ -0,0 +1,100 @@
+package input
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+    "unicode"
+)
+
+// Action is what the game does in response to a key
+type Action string
+
+const (
+    ActionUp    Action = "UP"
+    ActionDown  Action = "DOWN"
+    ActionLeft  Action = "LEFT"
+    ActionRight Action = "RIGHT"
+    ActionQuit  Action = "QUIT"
+    ActionPause Action = "PAUSE"
+)
+
+// DefaultBindings maps the arrow keys, WASD and vim keys to movement, ESC
+// and q to quit and p to pause
+var DefaultBindings = map[Action][]string{
+    ActionUp:    {"UP", "w", "k"},
+    ActionDown:  {"DOWN", "s", "j"},
+    ActionLeft:  {"LEFT", "a", "h"},
+    ActionRight: {"RIGHT", "d", "l"},
+    ActionQuit:  {"ESC", "q"},
+    ActionPause: {"p"},
+}
+
+// Bindings maps keys to actions
+type Bindings map[Key]Action
+
+// NewBindings builds the key bindings from a list of key names per action,
+// as found in the configuration file. Actions missing from keys keep their
+// default bindings, except for keys that keys binds to another action.
+// Binding the same key to two actions in keys is an error.
+func NewBindings(keys map[string][]string) (Bindings, error) {
+    // go through the configured actions in a fixed order, so that errors
+    // are the same from run to run
+    configured := make([]string, 0, len(keys))
+    for name := range keys {
+        configured = append(configured, name)
+    }
+    sort.Strings(configured)
+
+    b := Bindings{}
+    overridden := make(map[Action]bool, len(keys))
+    for _, name := range configured {
+        action := Action(strings.ToUpper(name))
+        if _, ok := DefaultBindings[action]; !ok {
+            return nil, fmt.Errorf("unknown action %q", name)
+        }
+        overridden[action] = true
+        for _, keyName := range keys[name] {
+            key, err := ParseKey(keyName)
+            if err != nil {
+                return nil, fmt.Errorf("binding for %s: %w", action, err)
+            }
+            if other, ok := b[key]; ok && other != action {
+                return nil, fmt.Errorf("key %q is bound to both %s and %s", keyName, other, action)
+            }
+            b[key] = action
+        }
+    }
+
+    for action, names := range DefaultBindings {
+        if overridden[action] {
+            continue
+        }
+        for _, name := range names {
+            key, err := ParseKey(name)
+            if err != nil {
+                return nil, fmt.Errorf("binding for %s: %w", action, err)
+            }
+            if _, ok := b[key]; !ok {
+                b[key] = action
+            }
+        }
+    }
+    return b, nil
+}
+
+// Action returns the action bound to a key. Letters match regardless of
+// case, so bindings keep working with caps lock on.
+func (b Bindings) Action(k Key) (Action, bool) {
+    if a, ok := b[k]; ok {
+        return a, true
+    }
+    if k.Code == KeyRune {
+        for _, r := range []rune{unicode.ToLower(k.Rune), unicode.ToUpper(k.Rune)} {
+            if a, ok := b[Key{Rune: r}]; ok {
+                return a, true
+            }
+        }
+    }
+    return "", false
+}
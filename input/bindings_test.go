@@ -0,0 +1,90 @@
+package input
+
+import "testing"
+
+func TestBindingsDefaults(t *testing.T) {
+    b, err := NewBindings(nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        key  Key
+        want Action
+    }{
+        {up, ActionUp},
+        {r('w'), ActionUp},
+        {r('W'), ActionUp},
+        {r('k'), ActionUp},
+        {r('h'), ActionLeft},
+        {r('d'), ActionRight},
+        {escK, ActionQuit},
+        {r('q'), ActionQuit},
+        {r('p'), ActionPause},
+    }
+    for _, tt := range tests {
+        got, ok := b.Action(tt.key)
+        if !ok || got != tt.want {
+            t.Errorf("Action(%v) = %q, %v, want %q", tt.key, got, ok, tt.want)
+        }
+    }
+
+    if a, ok := b.Action(r('x')); ok {
+        t.Errorf("Action(x) = %q, want no action", a)
+    }
+}
+
+func TestBindingsFromConfig(t *testing.T) {
+    b, err := NewBindings(map[string][]string{
+        "up":    {"UP", "i"},
+        "PAUSE": {"SPACE"},
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if a, _ := b.Action(r('i')); a != ActionUp {
+        t.Errorf("i is bound to %q, want %q", a, ActionUp)
+    }
+    if a, ok := b.Action(r('w')); ok {
+        t.Errorf("w is still bound to %q", a)
+    }
+    if a, _ := b.Action(r(' ')); a != ActionPause {
+        t.Errorf("space is bound to %q, want %q", a, ActionPause)
+    }
+    if a, _ := b.Action(r('s')); a != ActionDown {
+        t.Errorf("s is bound to %q, want default %q", a, ActionDown)
+    }
+}
+
+func TestBindingsConfigOverridesDefaultKey(t *testing.T) {
+    // k moves up by default, the configured pause binding takes it over
+    // every time
+    for i := 0; i < 20; i++ {
+        b, err := NewBindings(map[string][]string{"pause": {"k"}})
+        if err != nil {
+            t.Fatal(err)
+        }
+        if a, _ := b.Action(r('k')); a != ActionPause {
+            t.Fatalf("k is bound to %q, want %q", a, ActionPause)
+        }
+        if a, _ := b.Action(r('w')); a != ActionUp {
+            t.Fatalf("w is bound to %q, want default %q", a, ActionUp)
+        }
+        if a, ok := b.Action(r('p')); ok {
+            t.Fatalf("p is still bound to %q", a)
+        }
+    }
+}
+
+func TestBindingsErrors(t *testing.T) {
+    if _, err := NewBindings(map[string][]string{"jump": {"x"}}); err == nil {
+        t.Error("expected an error for an unknown action")
+    }
+    if _, err := NewBindings(map[string][]string{"up": {"xyz"}}); err == nil {
+        t.Error("expected an error for an unknown key")
+    }
+    if _, err := NewBindings(map[string][]string{"up": {"x"}, "pause": {"x"}}); err == nil {
+        t.Error("expected an error for a key bound to two actions")
+    }
+}
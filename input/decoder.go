@@ -0,0 +1,135 @@
+package input
+
+import "unicode/utf8"
+
+const esc = 0x1b
+
+// Decoder splits a stream of terminal input into keys. Escape sequences may
+// be split across several reads: an incomplete sequence at the end of the
+// data is kept until more data arrives or Flush is called.
+type Decoder struct {
+    pending []byte
+}
+
+// Feed decodes data and returns every complete key found in it
+func (d *Decoder) Feed(data []byte) []Key {
+    d.pending = append(d.pending, data...)
+
+    var keys []Key
+    for len(d.pending) > 0 {
+        key, n, ok := decode(d.pending)
+        if n == 0 {
+            // incomplete sequence, wait for more data
+            break
+        }
+        d.pending = d.pending[n:]
+        if ok {
+            keys = append(keys, key)
+        }
+    }
+
+    if len(d.pending) == 0 {
+        d.pending = nil
+    }
+    return keys
+}
+
+// Pending reports whether the decoder holds an incomplete sequence
+func (d *Decoder) Pending() bool {
+    return len(d.pending) > 0
+}
+
+// Flush gives up waiting for the rest of an incomplete sequence. A lone
+// escape byte becomes an ESC key press; anything after it is decoded on
+// its own.
+func (d *Decoder) Flush() []Key {
+    if len(d.pending) == 0 {
+        return nil
+    }
+
+    var keys []Key
+    if d.pending[0] == esc {
+        keys = append(keys, Key{Code: KeyEsc})
+        d.pending = d.pending[1:]
+    } else {
+        // a truncated UTF-8 character
+        d.pending = d.pending[1:]
+    }
+
+    rest := d.pending
+    d.pending = nil
+    keys = append(keys, d.Feed(rest)...)
+    return append(keys, d.Flush()...)
+}
+
+// decode decodes the key at the start of b. It returns the number of bytes
+// used, 0 if b holds an incomplete sequence, and ok=false for sequences
+// that are consumed but do not map to a key.
+func decode(b []byte) (key Key, n int, ok bool) {
+    switch c := b[0]; {
+    case c == esc:
+        return decodeEscape(b)
+    case c == '\r' || c == '\n':
+        return Key{Code: KeyEnter}, 1, true
+    case c == 0x7f || c == 0x08:
+        return Key{Code: KeyBackspace}, 1, true
+    case c < 0x20:
+        // other control characters are ignored
+        return Key{}, 1, false
+    case c < utf8.RuneSelf:
+        return Key{Rune: rune(c)}, 1, true
+    }
+
+    if !utf8.FullRune(b) {
+        return Key{}, 0, false
+    }
+    r, size := utf8.DecodeRune(b)
+    if r == utf8.RuneError {
+        return Key{}, size, false
+    }
+    return Key{Rune: r}, size, true
+}
+
+// decodeEscape decodes a sequence starting with ESC: a CSI sequence
+// (ESC [ ... final), an SS3 sequence (ESC O final) or a lone ESC
+func decodeEscape(b []byte) (key Key, n int, ok bool) {
+    if len(b) < 2 {
+        return Key{}, 0, false
+    }
+
+    switch b[1] {
+    case '[':
+        // parameters and intermediates are skipped up to the final byte,
+        // so modified arrows such as ESC [ 1 ; 5 A still count as arrows
+        for i := 2; i < len(b); i++ {
+            if b[i] >= 0x40 && b[i] <= 0x7e {
+                key, ok := arrow(b[i])
+                return key, i + 1, ok
+            }
+        }
+        return Key{}, 0, false
+    case 'O':
+        if len(b) < 3 {
+            return Key{}, 0, false
+        }
+        key, ok := arrow(b[2])
+        return key, 3, ok
+    default:
+        // ESC followed by a regular key, e.g. ESC pressed twice quickly
+        return Key{Code: KeyEsc}, 1, true
+    }
+}
+
+func arrow(final byte) (Key, bool) {
+    switch final {
+    case 'A':
+        return Key{Code: KeyUp}, true
+    case 'B':
+        return Key{Code: KeyDown}, true
+    case 'C':
+        return Key{Code: KeyRight}, true
+    case 'D':
+        return Key{Code: KeyLeft}, true
+    }
+    return Key{}, false
+}
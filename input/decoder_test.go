@@ -0,0 +1,102 @@
+package input
+
+import (
+    "reflect"
+    "testing"
+)
+
+var (
+    up    = Key{Code: KeyUp}
+    down  = Key{Code: KeyDown}
+    left  = Key{Code: KeyLeft}
+    right = Key{Code: KeyRight}
+    escK  = Key{Code: KeyEsc}
+    enter = Key{Code: KeyEnter}
+)
+
+func r(c rune) Key {
+    return Key{Rune: c}
+}
+
+func TestDecoderFeed(t *testing.T) {
+    tests := []struct {
+        name    string
+        input   string
+        want    []Key
+        pending bool
+    }{
+        {"csi arrow", "\x1b[A", []Key{up}, false},
+        {"ss3 arrow", "\x1bOD", []Key{left}, false},
+        {"two arrows in one read", "\x1b[A\x1b[B", []Key{up, down}, false},
+        {"mixed csi and ss3", "\x1b[C\x1bOA\x1b[D", []Key{right, up, left}, false},
+        {"modified arrow", "\x1b[1;5C", []Key{right}, false},
+        {"letters", "wasd", []Key{r('w'), r('a'), r('s'), r('d')}, false},
+        {"letters around arrows", "q\x1b[Bp", []Key{r('q'), down, r('p')}, false},
+        {"enter", "\r", []Key{enter}, false},
+        {"double escape", "\x1b\x1b[A", []Key{escK, up}, false},
+        {"unknown csi is skipped", "\x1b[2~k", []Key{r('k')}, false},
+        {"utf-8", "é", []Key{r('é')}, false},
+        {"lone escape waits", "\x1b", nil, true},
+        {"truncated csi waits", "\x1b[", nil, true},
+        {"truncated ss3 waits", "j\x1bO", []Key{r('j')}, true},
+        {"truncated utf-8 waits", "\xc3", nil, true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var d Decoder
+            got := d.Feed([]byte(tt.input))
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Errorf("Feed(%q) = %v, want %v", tt.input, got, tt.want)
+            }
+            if d.Pending() != tt.pending {
+                t.Errorf("Pending() = %v, want %v", d.Pending(), tt.pending)
+            }
+        })
+    }
+}
+
+func TestDecoderSplitReads(t *testing.T) {
+    reads := []string{"\x1b", "[", "A\x1b", "O", "B", "\xc3", "\xa9"}
+
+    var d Decoder
+    var got []Key
+    for _, read := range reads {
+        got = append(got, d.Feed([]byte(read))...)
+    }
+
+    want := []Key{up, down, r('é')}
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("got %v, want %v", got, want)
+    }
+    if d.Pending() {
+        t.Error("decoder still has pending bytes")
+    }
+}
+
+func TestDecoderFlush(t *testing.T) {
+    tests := []struct {
+        name  string
+        input string
+        want  []Key
+    }{
+        {"lone escape", "\x1b", []Key{escK}},
+        {"escape then bracket", "\x1b[", []Key{escK, r('[')}},
+        {"escape then O", "\x1bO", []Key{escK, r('O')}},
+        {"nothing pending", "", nil},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var d Decoder
+            d.Feed([]byte(tt.input))
+            got := d.Flush()
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Errorf("Flush() after %q = %v, want %v", tt.input, got, tt.want)
+            }
+            if d.Pending() {
+                t.Error("decoder still has pending bytes")
+            }
+        })
+    }
+}
@@ -0,0 +1,70 @@
+// Package input turns the raw bytes read from a terminal into key presses
+// and maps them to game actions.
+package input
+
+import (
+    "fmt"
+    "strings"
+    "unicode/utf8"
+)
+
+// KeyCode identifies a key that does not produce a printable character.
+// Printable characters use KeyRune together with Key.Rune.
+type KeyCode int
+
+const (
+    KeyRune KeyCode = iota
+    KeyUp
+    KeyDown
+    KeyLeft
+    KeyRight
+    KeyEsc
+    KeyEnter
+    KeyBackspace
+)
+
+var keyNames = map[KeyCode]string{
+    KeyUp:        "UP",
+    KeyDown:      "DOWN",
+    KeyLeft:      "LEFT",
+    KeyRight:     "RIGHT",
+    KeyEsc:       "ESC",
+    KeyEnter:     "ENTER",
+    KeyBackspace: "BACKSPACE",
+}
+
+// Key is a single key press
+type Key struct {
+    Code KeyCode
+    Rune rune
+}
+
+func (k Key) String() string {
+    if k.Code == KeyRune {
+        if k.Rune == ' ' {
+            return "SPACE"
+        }
+        return string(k.Rune)
+    }
+    return keyNames[k.Code]
+}
+
+// ParseKey parses a key name as used in key bindings: one of UP, DOWN,
+// LEFT, RIGHT, ESC, ENTER, BACKSPACE, SPACE, or a single character
+func ParseKey(name string) (Key, error) {
+    upper := strings.ToUpper(name)
+    for code, n := range keyNames {
+        if n == upper {
+            return Key{Code: code}, nil
+        }
+    }
+    if upper == "SPACE" {
+        return Key{Rune: ' '}, nil
+    }
+
+    r, size := utf8.DecodeRuneInString(name)
+    if r == utf8.RuneError || size != len(name) {
+        return Key{}, fmt.Errorf("unknown key %q", name)
+    }
+    return Key{Rune: r}, nil
+}
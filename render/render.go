@@ -0,0 +1,229 @@
+// Package render draws frames on an ANSI terminal. A frame is a grid of
+// maze cells followed by a few lines of text; only what changed since the
+// previous frame is sent to the terminal, in a single write. Grids smaller
+// than the terminal are centred, larger ones are scrolled to keep the
+// frame's focus in view.
+package render
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "strings"
+)
+
+// Frame is the content of the screen for one frame
+type Frame struct {
+    rows, cols int
+    cells      []string
+    lines      []string
+
+    // cell kept in view when the grid does not fit on the screen
+    focusRow, focusCol int
+    // text boxed in the middle of the view, over the grid
+    overlay []string
+}
+
+// NewFrame creates an empty frame with a grid of rows by cols cells
+func NewFrame(rows, cols int) *Frame {
+    return &Frame{
+        rows:  rows,
+        cols:  cols,
+        cells: make([]string, rows*cols),
+    }
+}
+
+// Set puts glyph in the cell at row, col, replacing what was there. glyph
+// may include ANSI colour sequences. Cells outside the grid are ignored.
+func (f *Frame) Set(row, col int, glyph string) {
+    if row < 0 || row >= f.rows || col < 0 || col >= f.cols {
+        return
+    }
+    f.cells[row*f.cols+col] = glyph
+}
+
+// Cell returns the glyph in the cell at row, col
+func (f *Frame) Cell(row, col int) string {
+    if row < 0 || row >= f.rows || col < 0 || col >= f.cols {
+        return ""
+    }
+    return f.cells[row*f.cols+col]
+}
+
+// SetFocus sets the cell to keep in view when the grid is larger than the
+// terminal
+func (f *Frame) SetFocus(row, col int) {
+    f.focusRow, f.focusCol = row, col
+}
+
+// SetOverlay sets lines of text to show in a box in the middle of the
+// grid, drawn over the cells. Every character of the text must take a
+// single column.
+func (f *Frame) SetOverlay(lines ...string) {
+    f.overlay = lines
+}
+
+// AddLine adds a line of text below the grid
+func (f *Frame) AddLine(text string) {
+    f.lines = append(f.lines, text)
+}
+
+// Renderer draws frames on a terminal, keeping what it drew last so that
+// the next frame only updates the cells and lines that differ
+type Renderer struct {
+    out       io.Writer
+    cellWidth int
+    // size of the terminal, zero when unknown
+    rows, cols int
+    // most status lines seen in a frame, kept free below the grid so that
+    // the layout does not move when a line comes and goes
+    statusLines int
+    tooSmall    bool
+    // message shown instead of the frame, if any
+    shown string
+
+    prev *view
+    buf  bytes.Buffer
+}
+
+// New creates a renderer writing to out. cellWidth is the number of
+// terminal columns taken by one cell of the grid.
+func New(out io.Writer, cellWidth int) *Renderer {
+    if cellWidth < 1 {
+        cellWidth = 1
+    }
+    return &Renderer{out: out, cellWidth: cellWidth}
+}
+
+// Resize tells the renderer the size of the terminal. The next Draw
+// redraws the whole screen.
+func (r *Renderer) Resize(rows, cols int) {
+    r.rows, r.cols = rows, cols
+    r.Invalidate()
+}
+
+// Invalidate forgets the last frame drawn, so that the next Draw clears the
+// screen and draws everything. It must be called when something else has
+// written to the terminal.
+func (r *Renderer) Invalidate() {
+    r.prev = nil
+    r.shown = ""
+}
+
+// TooSmall reports whether the last frame did not fit on the terminal, in
+// which case a message was shown instead
+func (r *Renderer) TooSmall() bool {
+    return r.tooSmall
+}
+
+// Draw brings the screen up to date with f and leaves the cursor on the
+// line below the frame
+func (r *Renderer) Draw(f *Frame) error {
+    r.buf.Reset()
+
+    if len(f.lines) > r.statusLines {
+        r.statusLines = len(f.lines)
+    }
+    v, ok := r.layout(f)
+    r.tooSmall = !ok
+    if !ok {
+        return r.drawTooSmall(f)
+    }
+    r.placeOverlay(v)
+
+    prev := r.prev
+    if prev == nil || !prev.samePlace(v) {
+        r.buf.WriteString("\x1b[2J")
+        prev = nil
+    }
+
+    for row := 0; row < v.rows; row++ {
+        // consecutive changed cells are written in one go, without moving
+        // the cursor in between
+        next := -1
+        for col := 0; col < v.cols; col++ {
+            glyph := v.cell(row, col)
+            if prev != nil && prev.cell(row, col) == glyph {
+                continue
+            }
+            if col != next {
+                r.moveCursor(v.top+row, v.left+col*r.cellWidth)
+            }
+            r.buf.WriteString(glyph)
+            next = -1
+            if Width(glyph) == r.cellWidth {
+                next = col + 1
+            }
+        }
+    }
+
+    lines := len(f.lines)
+    if prev != nil && len(prev.frame.lines) > lines {
+        lines = len(prev.frame.lines)
+    }
+    for i := 0; i < lines; i++ {
+        var text string
+        if i < len(f.lines) {
+            text = r.fit(f.lines[i], v.left)
+        }
+        if prev != nil && i < len(prev.frame.lines) && r.fit(prev.frame.lines[i], v.left) == text {
+            continue
+        }
+        r.moveCursor(v.top+v.rows+i, v.left)
+        r.buf.WriteString(text)
+        // clear what is left of a longer line drawn before
+        r.buf.WriteString("\x1b[K")
+    }
+
+    r.prev = v
+    r.shown = ""
+    if r.buf.Len() == 0 {
+        return nil
+    }
+
+    r.moveCursor(v.top+v.rows+len(f.lines), 0)
+    _, err := r.out.Write(r.buf.Bytes())
+    return err
+}
+
+// drawTooSmall replaces the screen with a message asking for a larger
+// terminal
+func (r *Renderer) drawTooSmall(f *Frame) error {
+    msg := []string{
+        "Terminal",
+        "too small",
+        fmt.Sprintf("is %dx%d", r.cols, r.rows),
+        fmt.Sprintf("need %dx%d", r.minCols(f), r.minRows(f)),
+    }
+    if r.prev == nil && r.shown == strings.Join(msg, "\n") {
+        return nil
+    }
+    r.prev = nil
+    r.shown = strings.Join(msg, "\n")
+
+    r.buf.WriteString("\x1b[2J")
+    for i, line := range msg {
+        if i >= r.rows {
+            break
+        }
+        r.moveCursor(i, 0)
+        r.buf.WriteString(r.fit(line, 0))
+    }
+    r.moveCursor(minInt(len(msg), r.rows-1), 0)
+
+    _, err := r.out.Write(r.buf.Bytes())
+    return err
+}
+
+// fit cuts text so that it fits on the terminal when drawn from column left
+func (r *Renderer) fit(text string, left int) string {
+    if r.cols == 0 {
+        return text
+    }
+    return Truncate(text, r.cols-left)
+}
+
+// moveCursor moves the cursor to the zero based row and column
+func (r *Renderer) moveCursor(row, col int) {
+    fmt.Fprintf(&r.buf, "\x1b[%d;%dH", row+1, col+1)
+}
@@ -0,0 +1,136 @@
+package render
+
+// overlayStyle shows overlay text in reverse video, resetStyle goes back
+// to normal
+const (
+    overlayStyle = "\x1b[7m"
+    resetStyle   = "\x1b[0m"
+)
+
+// minViewCells is the fewest rows and columns of the grid shown before the
+// terminal is considered too small to play on
+const minViewCells = 7
+
+// view is the part of a frame that is on the screen, and where it is
+type view struct {
+    frame *Frame
+    // screen row and column of the top left cell
+    top, left int
+    // first row and column of the grid in view
+    row0, col0 int
+    // number of rows and columns of the grid in view
+    rows, cols int
+    // cells covered by the frame's overlay, by row and column of the view
+    overlay map[[2]int]string
+}
+
+// cell returns the glyph at row, col of the view
+func (v *view) cell(row, col int) string {
+    if glyph, ok := v.overlay[[2]int{row, col}]; ok {
+        return glyph
+    }
+    return v.frame.Cell(v.row0+row, v.col0+col)
+}
+
+// samePlace reports whether o takes the same area of the screen as v, so
+// that one can be drawn over the other cell by cell
+func (v *view) samePlace(o *view) bool {
+    return v.top == o.top && v.left == o.left && v.rows == o.rows && v.cols == o.cols
+}
+
+// layout places f on the screen: centred if it fits, otherwise scrolled so
+// that its focus is in view. It reports false if the terminal is too small.
+func (r *Renderer) layout(f *Frame) (*view, bool) {
+    v := &view{frame: f, rows: f.rows, cols: f.cols}
+    if r.rows == 0 || r.cols == 0 {
+        return v, true
+    }
+
+    // the status lines and the line the cursor is left on go below the grid
+    free := r.rows - r.statusLines - 1
+    if v.rows > free {
+        v.rows = free
+    }
+    if v.cols > r.cols/r.cellWidth {
+        v.cols = r.cols / r.cellWidth
+    }
+    if v.rows < minInt(f.rows, minViewCells) || v.cols < minInt(f.cols, minViewCells) {
+        return nil, false
+    }
+
+    v.row0 = scroll(f.focusRow, v.rows, f.rows)
+    v.col0 = scroll(f.focusCol, v.cols, f.cols)
+    v.top = (free - v.rows) / 2
+    v.left = (r.cols - v.cols*r.cellWidth) / 2
+    return v, true
+}
+
+// minRows is the height of the smallest terminal f can be drawn on
+func (r *Renderer) minRows(f *Frame) int {
+    return minInt(f.rows, minViewCells) + r.statusLines + 1
+}
+
+// minCols is the width of the smallest terminal f can be drawn on
+func (r *Renderer) minCols(f *Frame) int {
+    return minInt(f.cols, minViewCells) * r.cellWidth
+}
+
+// scroll returns the first of size cells to show out of total so that
+// focus is as close to the middle as possible
+func scroll(focus, size, total int) int {
+    first := focus - size/2
+    if first > total-size {
+        first = total - size
+    }
+    if first < 0 {
+        first = 0
+    }
+    return first
+}
+
+// minInt returns the smaller of a and b
+func minInt(a, b int) int {
+    if a < b {
+        return a
+    }
+    return b
+}
+
+// placeOverlay lays the frame's overlay out in a box in the middle of v.
+// The box is cut into pieces one cell wide, which replace the cells under
+// them.
+func (r *Renderer) placeOverlay(v *view) {
+    lines := v.frame.overlay
+    if len(lines) == 0 {
+        return
+    }
+
+    width := 0
+    for _, line := range lines {
+        if w := Width(line); w > width {
+            width = w
+        }
+    }
+    // a space on each side, rounded up to whole cells
+    cells := (width + 2 + r.cellWidth - 1) / r.cellWidth
+    width = cells * r.cellWidth
+
+    top := (v.rows - len(lines)) / 2
+    left := (v.cols - cells) / 2
+    v.overlay = make(map[[2]int]string, len(lines)*cells)
+    for i, line := range lines {
+        row := top + i
+        if row < 0 || row >= v.rows {
+            continue
+        }
+        text := []rune(Pad(" "+line, width))
+        for c := 0; c < cells; c++ {
+            col := left + c
+            if col < 0 || col >= v.cols {
+                continue
+            }
+            piece := string(text[c*r.cellWidth : (c+1)*r.cellWidth])
+            v.overlay[[2]int{row, col}] = overlayStyle + piece + resetStyle
+        }
+    }
+}
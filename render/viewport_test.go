@@ -0,0 +1,148 @@
+package render
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+)
+
+// place is where a view is, without what is in it
+type place struct {
+    top, left, row0, col0, rows, cols int
+}
+
+func TestLayout(t *testing.T) {
+    tests := []struct {
+        name       string
+        cellWidth  int
+        termRows   int
+        termCols   int
+        frameRows  int
+        frameCols  int
+        focus      [2]int
+        want       place
+        wantTooBig bool
+    }{
+        {
+            name:      "unknown terminal size",
+            cellWidth: 1, frameRows: 50, frameCols: 100,
+            want: place{rows: 50, cols: 100},
+        },
+        {
+            name:      "small maze is centred",
+            cellWidth: 1, termRows: 24, termCols: 80, frameRows: 5, frameCols: 5,
+            want: place{top: 8, left: 37, rows: 5, cols: 5},
+        },
+        {
+            name:      "small maze with wide cells",
+            cellWidth: 2, termRows: 24, termCols: 80, frameRows: 5, frameCols: 5,
+            want: place{top: 8, left: 35, rows: 5, cols: 5},
+        },
+        {
+            // a status line and the cursor line go below the grid
+            name:      "exact fit",
+            cellWidth: 1, termRows: 24, termCols: 80, frameRows: 22, frameCols: 80,
+            want: place{rows: 22, cols: 80},
+        },
+        {
+            name:      "exact fit with wide cells",
+            cellWidth: 2, termRows: 24, termCols: 80, frameRows: 22, frameCols: 40,
+            want: place{rows: 22, cols: 40},
+        },
+        {
+            name:      "oversized maze follows the focus",
+            cellWidth: 1, termRows: 24, termCols: 80, frameRows: 50, frameCols: 100, focus: [2]int{25, 50},
+            want: place{row0: 14, col0: 10, rows: 22, cols: 80},
+        },
+        {
+            name:      "oversized maze stops at the top left",
+            cellWidth: 1, termRows: 24, termCols: 80, frameRows: 50, frameCols: 100, focus: [2]int{2, 3},
+            want: place{rows: 22, cols: 80},
+        },
+        {
+            name:      "oversized maze stops at the bottom right",
+            cellWidth: 1, termRows: 24, termCols: 80, frameRows: 50, frameCols: 100, focus: [2]int{49, 99},
+            want: place{row0: 28, col0: 20, rows: 22, cols: 80},
+        },
+        {
+            name:      "oversized in one direction only",
+            cellWidth: 2, termRows: 24, termCols: 81, frameRows: 10, frameCols: 50, focus: [2]int{5, 45},
+            want: place{top: 6, col0: 10, rows: 10, cols: 40},
+        },
+        {
+            name:      "terminal too small",
+            cellWidth: 1, termRows: 8, termCols: 80, frameRows: 50, frameCols: 100,
+            wantTooBig: true,
+        },
+        {
+            name:      "terminal too narrow",
+            cellWidth: 2, termRows: 24, termCols: 13, frameRows: 50, frameCols: 100,
+            wantTooBig: true,
+        },
+        {
+            // mazes smaller than the minimum view only need to fit
+            name:      "tiny maze on a tiny terminal",
+            cellWidth: 1, termRows: 5, termCols: 3, frameRows: 3, frameCols: 3,
+            want: place{rows: 3, cols: 3},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            r := New(&bytes.Buffer{}, tt.cellWidth)
+            r.Resize(tt.termRows, tt.termCols)
+            r.statusLines = 1
+            f := NewFrame(tt.frameRows, tt.frameCols)
+            f.SetFocus(tt.focus[0], tt.focus[1])
+
+            v, ok := r.layout(f)
+            if ok == tt.wantTooBig {
+                t.Fatalf("layout() fits = %v, want %v", ok, !tt.wantTooBig)
+            }
+            if !ok {
+                return
+            }
+            got := place{top: v.top, left: v.left, row0: v.row0, col0: v.col0, rows: v.rows, cols: v.cols}
+            if got != tt.want {
+                t.Errorf("layout() = %+v, want %+v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestDrawTooSmall(t *testing.T) {
+    var out bytes.Buffer
+    r := New(&out, 2)
+    r.Resize(6, 12)
+
+    f := NewFrame(20, 20)
+    f.AddLine("score")
+    if err := r.Draw(f); err != nil {
+        t.Fatal(err)
+    }
+    if !r.TooSmall() {
+        t.Errorf("TooSmall() = false on a 12x6 terminal")
+    }
+    for _, want := range []string{"too small", "is 12x6", "need 14x9"} {
+        if !strings.Contains(out.String(), want) {
+            t.Errorf("output %q does not say %q", out.String(), want)
+        }
+    }
+
+    // the same message is not drawn twice
+    out.Reset()
+    if err := r.Draw(f); err != nil {
+        t.Fatal(err)
+    }
+    if out.Len() != 0 {
+        t.Errorf("message drawn again: %q", out.String())
+    }
+
+    // once the terminal is large enough the frame is drawn again
+    r.Resize(24, 80)
+    if err := r.Draw(f); err != nil {
+        t.Fatal(err)
+    }
+    if r.TooSmall() || !strings.Contains(out.String(), "score") {
+        t.Errorf("frame not drawn after the terminal grew: %q", out.String())
+    }
+}
@@ -0,0 +1,216 @@
+package render
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+    "unicode"
+)
+
+// MaxCellWidth is the widest a cell can be. Wider glyphs would not line up
+// with the rest of the maze.
+const MaxCellWidth = 2
+
+const (
+    zeroWidthJoiner = '\u200d'
+    // text and emoji presentation selectors
+    variationText  = '\ufe0e'
+    variationEmoji = '\ufe0f'
+)
+
+// wideRanges are the code points that take two columns: East Asian Wide
+// and Fullwidth characters, and emoji that are drawn as emoji by default
+var wideRanges = [][2]rune{
+    {0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec},
+    {0x23f0, 0x23f0}, {0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615},
+    {0x2648, 0x2653}, {0x267f, 0x267f}, {0x2693, 0x2693}, {0x26a1, 0x26a1},
+    {0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5}, {0x26ce, 0x26ce},
+    {0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+    {0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b},
+    {0x2728, 0x2728}, {0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755},
+    {0x2757, 0x2757}, {0x2795, 0x2797}, {0x27b0, 0x27b0}, {0x27bf, 0x27bf},
+    {0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x2e80, 0x303e},
+    {0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+    {0xa960, 0xa97f}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe10, 0xfe19},
+    {0xfe30, 0xfe6f}, {0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x16fe0, 0x16fe4},
+    {0x17000, 0x18cff}, {0x1b000, 0x1b2ff}, {0x1f004, 0x1f004}, {0x1f0cf, 0x1f0cf},
+    {0x1f18e, 0x1f18e}, {0x1f191, 0x1f19a}, {0x1f1e6, 0x1f1ff}, {0x1f200, 0x1f202},
+    {0x1f210, 0x1f23b}, {0x1f240, 0x1f248}, {0x1f250, 0x1f251}, {0x1f260, 0x1f265},
+    {0x1f300, 0x1f320}, {0x1f32d, 0x1f335}, {0x1f337, 0x1f37c}, {0x1f37e, 0x1f393},
+    {0x1f3a0, 0x1f3ca}, {0x1f3cf, 0x1f3d3}, {0x1f3e0, 0x1f3f0}, {0x1f3f4, 0x1f3f4},
+    {0x1f3f8, 0x1f43e}, {0x1f440, 0x1f440}, {0x1f442, 0x1f4fc}, {0x1f4ff, 0x1f53d},
+    {0x1f54b, 0x1f54e}, {0x1f550, 0x1f567}, {0x1f57a, 0x1f57a}, {0x1f595, 0x1f596},
+    {0x1f5a4, 0x1f5a4}, {0x1f5fb, 0x1f64f}, {0x1f680, 0x1f6c5}, {0x1f6cc, 0x1f6cc},
+    {0x1f6d0, 0x1f6d2}, {0x1f6d5, 0x1f6d7}, {0x1f6dc, 0x1f6df}, {0x1f6eb, 0x1f6ec},
+    {0x1f6f4, 0x1f6fc}, {0x1f7e0, 0x1f7eb}, {0x1f7f0, 0x1f7f0}, {0x1f90c, 0x1f93a},
+    {0x1f93c, 0x1f945}, {0x1f947, 0x1f9ff}, {0x1fa70, 0x1faff}, {0x20000, 0x2fffd},
+    {0x30000, 0x3fffd},
+}
+
+// runeWidth is the number of columns a single code point takes on its own
+func runeWidth(r rune) int {
+    switch {
+    case r == 0 || r < 0x20 || (r >= 0x7f && r < 0xa0):
+        return 0
+    case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+        // combining marks, joiners and selectors attach to the previous
+        // character
+        return 0
+    case r >= 0x1f3fb && r <= 0x1f3ff:
+        // skin tone modifiers
+        return 0
+    case r < 0x1100:
+        return 1
+    }
+
+    i := sort.Search(len(wideRanges), func(i int) bool {
+        return wideRanges[i][1] >= r
+    })
+    if i < len(wideRanges) && wideRanges[i][0] <= r {
+        return 2
+    }
+    return 1
+}
+
+// isRegionalIndicator reports whether r is one of the letters that pair up
+// into a flag
+func isRegionalIndicator(r rune) bool {
+    return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// Width returns the number of terminal columns s takes. ANSI escape
+// sequences take none. Characters joined by a zero width joiner, flags and
+// characters followed by a variation selector are measured as the single
+// symbol a terminal draws for them.
+func Width(s string) int {
+    return measure(s, nil)
+}
+
+// measure returns the width of s as described for Width. If fn is not nil
+// it is called before each character and escape sequence with its byte
+// offset in s and the width of s up to there; measuring stops early, and
+// returns the width so far, as soon as fn returns false.
+func measure(s string, fn func(offset, width int) bool) int {
+    var runes []rune
+    var offsets []int
+    for i, r := range s {
+        runes = append(runes, r)
+        offsets = append(offsets, i)
+    }
+
+    width := 0
+    // width of the last symbol, whether the next character is joined to
+    // it and whether it is the first letter of a flag
+    last, joined, flag := 0, false, false
+
+    for i := 0; i < len(runes); i++ {
+        r := runes[i]
+        if fn != nil && !fn(offsets[i], width) {
+            return width
+        }
+
+        if r == '\x1b' {
+            i = skipEscape(runes, i)
+            continue
+        }
+
+        switch {
+        case r == zeroWidthJoiner:
+            joined = true
+            continue
+        case r == variationEmoji && last == 1:
+            width++
+            last = 2
+            continue
+        case r == variationText && last == 2:
+            width--
+            last = 1
+            continue
+        case joined:
+            joined = false
+            continue
+        case isRegionalIndicator(r) && flag:
+            // second letter of a flag
+            flag = false
+            continue
+        }
+
+        w := runeWidth(r)
+        if w == 0 {
+            continue
+        }
+        flag = isRegionalIndicator(r)
+        width += w
+        last = w
+    }
+    return width
+}
+
+// skipEscape returns the index of the last rune of the escape sequence
+// starting at i
+func skipEscape(runes []rune, i int) int {
+    if i+1 >= len(runes) || runes[i+1] != '[' {
+        return i + 1
+    }
+    for i += 2; i < len(runes); i++ {
+        if runes[i] >= 0x40 && runes[i] <= 0x7e {
+            return i
+        }
+    }
+    return i
+}
+
+// Pad appends spaces to glyph so that it takes width columns
+func Pad(glyph string, width int) string {
+    if w := Width(glyph); w < width {
+        return glyph + strings.Repeat(" ", width-w)
+    }
+    return glyph
+}
+
+// CellWidth returns the cell width that fits all the given glyphs, keyed by
+// name. A glyph wider than MaxCellWidth columns is an error.
+func CellWidth(glyphs map[string]string) (int, error) {
+    names := make([]string, 0, len(glyphs))
+    for name := range glyphs {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+
+    width := 1
+    var bad []string
+    for _, name := range names {
+        w := Width(glyphs[name])
+        if w > MaxCellWidth {
+            bad = append(bad, fmt.Sprintf("%s %q is %d columns wide", name, glyphs[name], w))
+        }
+        if w > width {
+            width = w
+        }
+    }
+    if len(bad) > 0 {
+        return 0, fmt.Errorf("glyphs must be at most %d columns wide: %s", MaxCellWidth, strings.Join(bad, ", "))
+    }
+    return width, nil
+}
+
+// Truncate cuts s so that it takes at most width columns
+func Truncate(s string, width int) string {
+    if Width(s) <= width {
+        return s
+    }
+
+    end := 0
+    measure(s, func(offset, w int) bool {
+        if w > width {
+            return false
+        }
+        end = offset
+        return true
+    })
+    if strings.Contains(s, "\x1b") {
+        // the cut may fall inside a coloured part
+        return s[:end] + "\x1b[0m"
+    }
+    return s[:end]
+}
@@ -0,0 +1,130 @@
+package render
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestWidthAndPad(t *testing.T) {
+    tests := []struct {
+        name  string
+        s     string
+        width int
+    }{
+        {"empty", "", 0},
+        {"ascii", "abc", 3},
+        {"cjk", "中文", 4},
+        {"ascii and cjk", "a中", 3},
+        {"combining mark", "e\u0301", 1},
+        {"emoji", "👻", 2},
+        {"emoji presentation selector", "\u25ab\ufe0f", 2},
+        {"text presentation selector", "\u231a\ufe0e", 1},
+        {"zwj family", "\U0001f468\u200d\U0001f469\u200d\U0001f467", 2},
+        {"flag", "🇯🇵", 2},
+        {"two flags", "🇯🇵🇫🇷", 4},
+        {"skin tone modifier", "\U0001f44d\U0001f3fd", 2},
+        {"ansi colour", "\x1b[31mab\x1b[0m", 2},
+        {"ansi around emoji", "\x1b[1;33m👻\x1b[0m", 2},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Width(tt.s); got != tt.width {
+                t.Errorf("Width(%q) = %d, want %d", tt.s, got, tt.width)
+            }
+
+            // padding to a wider cell adds the missing columns as spaces,
+            // padding to a narrower one leaves s alone
+            padded := Pad(tt.s, tt.width+2)
+            if padded != tt.s+"  " {
+                t.Errorf("Pad(%q, %d) = %q, want two spaces added", tt.s, tt.width+2, padded)
+            }
+            if got := Pad(tt.s, tt.width); got != tt.s {
+                t.Errorf("Pad(%q, %d) = %q, want it unchanged", tt.s, tt.width, got)
+            }
+            if tt.width > 0 {
+                if got := Pad(tt.s, tt.width-1); got != tt.s {
+                    t.Errorf("Pad(%q, %d) = %q, want it unchanged", tt.s, tt.width-1, got)
+                }
+            }
+        })
+    }
+}
+
+func TestCellWidth(t *testing.T) {
+    tests := []struct {
+        name    string
+        glyphs  map[string]string
+        want    int
+        wantErr string
+    }{
+        {name: "ascii", glyphs: map[string]string{"wall": "#", "dot": "."}, want: 1},
+        {name: "emoji", glyphs: map[string]string{"player": "😋", "dot": "▫️"}, want: 2},
+        // narrower glyphs are padded to the widest one
+        {name: "mixed widths that fit a cell", glyphs: map[string]string{"wall": "#", "player": "😋", "space": ""}, want: 2},
+        {
+            name:    "mixed widths that do not fit a cell",
+            glyphs:  map[string]string{"wall": "#", "player": "😋", "ghost": "👻👻", "dot": "abc"},
+            wantErr: `glyphs must be at most 2 columns wide: dot "abc" is 3 columns wide, ghost "👻👻" is 4 columns wide`,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := CellWidth(tt.glyphs)
+            if tt.wantErr != "" {
+                if err == nil || err.Error() != tt.wantErr {
+                    t.Errorf("CellWidth() error = %v, want %s", err, tt.wantErr)
+                }
+                return
+            }
+            if err != nil || got != tt.want {
+                t.Errorf("CellWidth() = %d, %v, want %d", got, err, tt.want)
+            }
+            for name, glyph := range tt.glyphs {
+                if w := Width(Pad(glyph, got)); w != got {
+                    t.Errorf("%s padded to %d columns is %d wide", name, got, w)
+                }
+            }
+        })
+    }
+}
+
+func TestWidthIgnoresUnfinishedEscape(t *testing.T) {
+    for _, s := range []string{"ab\x1b", "ab\x1b[", "ab\x1b[31"} {
+        if got := Width(s); got != 2 {
+            t.Errorf("Width(%q) = %d, want 2", s, got)
+        }
+    }
+    if got := Width(strings.Repeat("\x1b[0m", 3)); got != 0 {
+        t.Errorf("Width of escapes only = %d, want 0", got)
+    }
+}
+
+func TestTruncate(t *testing.T) {
+    tests := []struct {
+        name  string
+        s     string
+        width int
+        want  string
+    }{
+        {"fits", "score", 5, "score"},
+        {"ascii", "score: 120", 5, "score"},
+        {"zero width", "abc", 0, ""},
+        {"wide character not split", "ab中文", 3, "ab"},
+        {"wide character fits", "ab中文", 4, "ab中"},
+        {"emoji with selector kept whole", "a\u25ab\ufe0fb", 3, "a\u25ab\ufe0f"},
+        {"zwj sequence kept whole", "\U0001f468\u200d\U0001f469x", 2, "\U0001f468\u200d\U0001f469"},
+        {"colour reset after the cut", "\x1b[31mred text\x1b[0m", 3, "\x1b[31mred\x1b[0m"},
+        {"escapes do not count", "\x1b[1mab\x1b[0mcd", 3, "\x1b[1mab\x1b[0mc\x1b[0m"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := Truncate(tt.s, tt.width)
+            if got != tt.want {
+                t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+            }
+            if w := Width(got); w > tt.width {
+                t.Errorf("Truncate(%q, %d) is %d columns wide", tt.s, tt.width, w)
+            }
+        })
+    }
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+    "fmt"
+
+    "github.com/danicat/simpleansi"
+
+    "github.com/hd2yao/pac-man/game"
+    "github.com/hd2yao/pac-man/render"
+)
+
+// glyphSet holds the configured glyphs padded to the width of a maze cell,
+// so that every tile lines up whatever its own width
+type glyphSet struct {
+    width int
+
+    player    string
+    ghost     string
+    ghostBlue string
+    ghostEyes string
+    wall      string
+    dot       string
+    pill      string
+    death     string
+    space     string
+    fruits    map[string]string
+
+    // frames of the death animation
+    deathFrames []string
+}
+
+// loadGlyphs measures the glyphs of c and pads them to a common cell
+// width. Glyphs too wide to fit in a cell are reported as an error.
+func loadGlyphs(c game.Config) (*glyphSet, error) {
+    all := map[string]string{
+        "player":     c.Player,
+        "ghost":      c.Ghost,
+        "ghost_blue": c.GhostBlue,
+        "ghost_eyes": c.GhostEyes,
+        "wall":       c.Wall,
+        "dot":        c.Dot,
+        "pill":       c.Pill,
+        "death":      c.Death,
+        "space":      c.Space,
+    }
+    for name, glyph := range c.Fruits {
+        all["fruits."+name] = glyph
+    }
+    for i, glyph := range c.DeathFrames {
+        all[fmt.Sprintf("death_frames[%d]", i)] = glyph
+    }
+
+    width, err := render.CellWidth(all)
+    if err != nil {
+        return nil, fmt.Errorf("invalid config: %w", err)
+    }
+
+    pad := func(glyph string) string {
+        return render.Pad(glyph, width)
+    }
+    gs := &glyphSet{
+        width:     width,
+        player:    pad(c.Player),
+        ghost:     pad(c.Ghost),
+        ghostBlue: pad(c.GhostBlue),
+        ghostEyes: pad(c.GhostEyes),
+        wall:      simpleansi.WithBlueBackground(pad(c.Wall)),
+        dot:       pad(c.Dot),
+        pill:      pad(c.Pill),
+        death:     pad(c.Death),
+        space:     pad(c.Space),
+        fruits:    make(map[string]string, len(c.Fruits)),
+    }
+    for name, glyph := range c.Fruits {
+        gs.fruits[name] = pad(glyph)
+    }
+    for _, glyph := range c.DeathFrames {
+        gs.deathFrames = append(gs.deathFrames, pad(glyph))
+    }
+    if len(gs.deathFrames) == 0 {
+        // blink between the player and the death glyph
+        gs.deathFrames = []string{gs.player, gs.death, gs.player, gs.death}
+    }
+    return gs, nil
+}
+
+// deathFrame returns the frame of the death animation to show when it has
+// got as far as progress, between 0 and 1
+func (gs *glyphSet) deathFrame(progress float64) string {
+    i := int(progress * float64(len(gs.deathFrames)))
+    if i >= len(gs.deathFrames) {
+        i = len(gs.deathFrames) - 1
+    }
+    return gs.deathFrames[i]
+}
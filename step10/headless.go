@@ -0,0 +1,48 @@
+package main
+
+import (
+    "encoding/json"
+    "io"
+    "os"
+
+    "github.com/hd2yao/pac-man/game"
+)
+
+// headlessInput returns the input source of a headless game: the replay if
+// one was given, otherwise the script file or standard input
+func headlessInput(replay *game.Replay) (func(tick int) string, error) {
+    if replay != nil {
+        return replay.Input, nil
+    }
+
+    in := os.Stdin
+    if *scriptFile != "" && *scriptFile != "-" {
+        f, err := os.Open(*scriptFile)
+        if err != nil {
+            return nil, err
+        }
+        defer f.Close()
+        in = f
+    }
+
+    script, err := game.ParseScript(in)
+    if err != nil {
+        return nil, err
+    }
+    return script.Input, nil
+}
+
+// runHeadless plays the game as fast as possible without touching the
+// terminal and writes a JSON summary to out once the game ends or the tick
+// limit is reached
+func runHeadless(out io.Writer, g *game.Game, inputAt func(tick int) string, recording *game.Replay) error {
+    for !g.IsOver() && g.Tick() < *maxTicks {
+        inp := inputAt(g.Tick() + 1)
+        g.Step(inp)
+        if recording != nil {
+            recording.Record(g.Tick(), inp)
+        }
+    }
+
+    return json.NewEncoder(out).Encode(g.Summary())
+}
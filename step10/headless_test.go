@@ -0,0 +1,66 @@
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "reflect"
+    "strings"
+    "testing"
+
+    "github.com/hd2yao/pac-man/game"
+)
+
+func TestRunHeadlessSummary(t *testing.T) {
+    script, err := game.ParseScript(strings.NewReader("LEFT\n+10 ESC\n"))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    g := newTestGame(t, 3)
+    rec := &game.Replay{Seed: 3}
+    var out bytes.Buffer
+    if err := runHeadless(&out, g, script.Input, rec); err != nil {
+        t.Fatal(err)
+    }
+
+    var got map[string]interface{}
+    if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+        t.Fatalf("summary %q is not JSON: %v", out.String(), err)
+    }
+    want := map[string]interface{}{
+        "seed":      3.0,
+        "ticks":     11.0,
+        "score":     got["score"],
+        "lives":     0.0,
+        "level":     1.0,
+        "dots_left": got["dots_left"],
+        "outcome":   "lost",
+    }
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("summary = %v, want %v", got, want)
+    }
+
+    wantInputs := []game.InputEvent{{Tick: 1, Input: "LEFT"}, {Tick: 11, Input: "ESC"}}
+    if !reflect.DeepEqual(rec.Inputs, wantInputs) {
+        t.Errorf("recorded inputs = %v, want %v", rec.Inputs, wantInputs)
+    }
+}
+
+func TestRunHeadlessStopsAtMaxTicks(t *testing.T) {
+    defer func(n int) { *maxTicks = n }(*maxTicks)
+    *maxTicks = 50
+
+    g := newTestGame(t, 3)
+    var out bytes.Buffer
+    if err := runHeadless(&out, g, func(int) string { return "" }, nil); err != nil {
+        t.Fatal(err)
+    }
+
+    var got game.Summary
+    if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+        t.Fatal(err)
+    }
+    if got.Ticks != 50 || got.Outcome != game.OutcomeRunning {
+        t.Errorf("summary = %+v, want a running game stopped after 50 ticks", got)
+    }
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "path/filepath"
+    "strings"
+    "time"
+    "unicode"
+
+    "github.com/hd2yao/pac-man/game"
+    "github.com/hd2yao/pac-man/highscore"
+    "github.com/hd2yao/pac-man/input"
+)
+
+// loadHighScores reads the high score table. If it cannot be read the game
+// goes on with an empty table, and the returned path is empty so that the
+// broken file is not overwritten.
+func loadHighScores() (string, *highscore.Table) {
+    path := *scoresFile
+    if path == "" {
+        var err error
+        path, err = highscore.DefaultPath()
+        if err != nil {
+            log.Println("high scores disabled:", err)
+            return "", &highscore.Table{}
+        }
+    }
+
+    scores, err := highscore.Load(path)
+    if err != nil {
+        log.Println("failed to load high scores:", err)
+        return "", &highscore.Table{}
+    }
+    return path, scores
+}
+
+// mazeName is the name the high score table shows for the levels played
+func mazeName(levelsPath, mazePath string) string {
+    if levelsPath != "" {
+        return strings.TrimSuffix(filepath.Base(levelsPath), filepath.Ext(levelsPath))
+    }
+    return strings.TrimSuffix(filepath.Base(mazePath), filepath.Ext(mazePath))
+}
+
+// enterInitials shows the initials entry prompt and returns up to three
+// letters or digits typed by the player, confirmed with enter. ESC skips the
+// entry and returns an empty string.
+func enterInitials(keys <-chan input.Key) string {
+    var initials []rune
+    for {
+        fmt.Printf("\r\x1b[KNEW HIGH SCORE! Enter your initials: %-3s", string(initials))
+
+        key, ok := <-keys
+        if !ok {
+            return string(initials)
+        }
+
+        switch {
+        case key.Code == input.KeyEnter && len(initials) > 0:
+            fmt.Println()
+            return string(initials)
+        case key.Code == input.KeyEsc:
+            fmt.Println()
+            return ""
+        case key.Code == input.KeyBackspace && len(initials) > 0:
+            initials = initials[:len(initials)-1]
+        case key.Code == input.KeyRune && len(initials) < 3 &&
+            (unicode.IsLetter(key.Rune) || unicode.IsDigit(key.Rune)):
+            initials = append(initials, unicode.ToUpper(key.Rune))
+        }
+    }
+}
+
+// recordHighScore asks for the player's initials if the score made it into
+// the table, saves the table and shows it
+func recordHighScore(g *game.Game, scores *highscore.Table, path string, keys <-chan input.Key, maze string) {
+    s := g.Snapshot()
+    rank := -1
+
+    if scores.Qualifies(s.Score) {
+        initials := enterInitials(keys)
+        if initials != "" {
+            rank = scores.Add(highscore.Entry{
+                Initials: initials,
+                Score:    s.Score,
+                Date:     time.Now(),
+                Level:    s.Level,
+                Maze:     maze,
+            })
+
+            err := scores.Save(path)
+            if err != nil {
+                log.Println("failed to save high scores:", err)
+            }
+        }
+    }
+
+    printHighScores(scores, rank)
+}
+
+// printHighScores lists the table, marking the entry at rank
+func printHighScores(scores *highscore.Table, rank int) {
+    if len(scores.Entries) == 0 {
+        return
+    }
+
+    fmt.Println()
+    fmt.Println("HIGH SCORES")
+    for i, e := range scores.Entries {
+        marker := " "
+        if i == rank {
+            marker = ">"
+        }
+        fmt.Printf("%s%2d. %-3s %8d  level %-3d %-12s %s\n",
+            marker, i+1, e.Initials, e.Score, e.Level, e.Maze, e.Date.Format("2006-01-02"))
+    }
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+    "context"
+    "io"
+    "log"
+    "time"
+
+    "github.com/hd2yao/pac-man/input"
+)
+
+// inputBuffer is how many decoded keys may queue up waiting for a tick
+const inputBuffer = 16
+
+// escTimeout is how long to wait for the rest of an escape sequence before
+// treating a lone escape byte as the ESC key
+const escTimeout = 25 * time.Millisecond
+
+// pollInterval is how often the input reader checks whether it has been
+// asked to stop while no key is pressed
+const pollInterval = 50 * time.Millisecond
+
+// keySource is where key presses are read from, normally the terminal
+type keySource interface {
+    io.Reader
+    WaitForInput(timeout time.Duration) (bool, error)
+}
+
+// readInput decodes key presses from src and sends them to ch until ctx is
+// done. The channel is closed when readInput returns, which it also does
+// when src can no longer be read.
+func readInput(ctx context.Context, src keySource, ch chan<- input.Key) {
+    defer close(ch)
+
+    var decoder input.Decoder
+    buffer := make([]byte, 100)
+
+    for ctx.Err() == nil {
+        ready, err := src.WaitForInput(pollInterval)
+        if err != nil {
+            log.Print("error reading input:", err)
+            return
+        }
+        if !ready {
+            continue
+        }
+
+        cnt, err := src.Read(buffer)
+        if err != nil {
+            log.Print("error reading input:", err)
+            return
+        }
+
+        keys := decoder.Feed(buffer[:cnt])
+        if decoder.Pending() {
+            more, err := src.WaitForInput(escTimeout)
+            if err == nil && !more {
+                keys = append(keys, decoder.Flush()...)
+            }
+        }
+
+        for _, key := range keys {
+            select {
+            case ch <- key:
+            case <-ctx.Done():
+                return
+            }
+        }
+    }
+}
+
+// gameInput translates a key into the game's input: a direction, "ESC" for
+// the quit action, the pause action, or an empty string for unbound keys
+func gameInput(bindings input.Bindings, key input.Key) string {
+    action, ok := bindings.Action(key)
+    if !ok {
+        return ""
+    }
+    if action == input.ActionQuit {
+        return "ESC"
+    }
+    return string(action)
+}
+
+// drainInput discards keys typed so far
+func drainInput(keys <-chan input.Key) {
+    for {
+        select {
+        case _, ok := <-keys:
+            if !ok {
+                return
+            }
+        default:
+            return
+        }
+    }
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+    "context"
+    "time"
+
+    "github.com/hd2yao/pac-man/game"
+    "github.com/hd2yao/pac-man/input"
+)
+
+// maxCatchUp bounds the number of ticks simulated in a row when the loop
+// falls behind, so a long stall does not turn into a burst of movement
+const maxCatchUp = 5
+
+// gameLoop plays a game in real time. The goroutine running it is the only
+// one touching the game: key presses, clock ticks and terminal resizes all
+// reach it as messages on channels.
+type gameLoop struct {
+    game     *game.Game
+    bindings input.Bindings
+
+    // keys typed by the player, nil when replaying
+    keys <-chan input.Key
+    // receives the time about once per tick of game time
+    clock <-chan time.Time
+    // the length of a tick. The loop steps through every tick due when the
+    // clock sends, catching up when messages were late or dropped. Zero
+    // steps once per clock message.
+    tick time.Duration
+    // when the next tick is due
+    next time.Time
+    // receives when the terminal was resized, may be nil
+    resized <-chan struct{}
+
+    replay    *game.Replay
+    recording *game.Replay
+
+    // draw shows the game, with the pause menu over it when menu is not
+    // nil. resize adapts the screen to a new terminal size and hold
+    // reports whether the game must wait because it cannot be seen.
+    // restart starts a new game, with a new recording if the game is being
+    // recorded. setSpeed changes how fast the clock ticks and how long a
+    // tick is. Only draw is required.
+    draw     func(s game.Snapshot, menu []string) error
+    resize   func()
+    hold     func() bool
+    restart  func() (*game.Game, *game.Replay)
+    setSpeed func(speed float64)
+
+    settings settings
+    // the pause menu, nil while playing
+    menu *pauseMenu
+    // inputs received but not yet given to the game, one per tick
+    pending []string
+}
+
+// run plays until the game is over or ctx is done
+func (l *gameLoop) run(ctx context.Context) error {
+    err := l.redraw()
+    if err != nil {
+        return err
+    }
+
+    for !l.done() {
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        case <-l.resized:
+            if l.resize != nil {
+                l.resize()
+            }
+        case key, ok := <-l.keys:
+            if !ok {
+                // the terminal is gone, quit the game
+                l.keys, l.menu = nil, nil
+                l.pending = append(l.pending[:0], "ESC")
+                break
+            }
+            if l.menu != nil {
+                l.menuKey(key)
+            } else {
+                l.queue(gameInput(l.bindings, key))
+            }
+        case now := <-l.clock:
+            l.advance(now)
+        }
+
+        err := l.redraw()
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// redraw shows the current state of the game
+func (l *gameLoop) redraw() error {
+    var menu []string
+    if l.menu != nil {
+        menu = l.menu.lines(l.settings)
+    }
+    return l.draw(l.game.Snapshot(), menu)
+}
+
+// queue keeps a game input for the next tick. The pause action opens the
+// pause menu straight away.
+func (l *gameLoop) queue(inp string) {
+    switch {
+    case inp == "":
+    case inp == string(input.ActionPause):
+        l.menu = &pauseMenu{}
+        l.pending = l.pending[:0]
+    case len(l.pending) < inputBuffer:
+        l.pending = append(l.pending, inp)
+    }
+}
+
+// menuKey passes a key press to the pause menu and carries out what was
+// picked
+func (l *gameLoop) menuKey(key input.Key) {
+    speed := l.settings.speed
+    choice := l.menu.handle(key, l.bindings, &l.settings)
+    if l.settings.speed != speed && l.setSpeed != nil {
+        l.setSpeed(l.settings.speed)
+    }
+
+    switch choice {
+    case choiceResume:
+        l.resume()
+    case choiceRestart:
+        if l.restart != nil {
+            l.game, l.recording = l.restart()
+        }
+        l.resume()
+    case choiceQuit:
+        l.resume()
+        l.pending = append(l.pending, "ESC")
+    }
+}
+
+// resume closes the pause menu. Inputs from before the pause and keys
+// still waiting to be read are dropped, so the game does not get a burst
+// of moves the moment it starts again.
+func (l *gameLoop) resume() {
+    l.menu = nil
+    l.pending = l.pending[:0]
+    for {
+        select {
+        case _, ok := <-l.keys:
+            if !ok {
+                // the terminal is gone, quit the game
+                l.keys = nil
+                l.pending = append(l.pending, "ESC")
+                return
+            }
+        default:
+            return
+        }
+    }
+}
+
+// advance steps through the ticks due by now, at most maxCatchUp of them.
+// After a longer stall the game carries on from now instead of rushing to
+// make up the lost time.
+func (l *gameLoop) advance(now time.Time) {
+    if l.tick <= 0 {
+        l.step()
+        return
+    }
+    if l.next.IsZero() {
+        l.next = now
+    }
+
+    steps := 0
+    for !now.Before(l.next) && steps < maxCatchUp && !l.done() {
+        l.step()
+        l.next = l.next.Add(l.tick)
+        steps++
+    }
+    if steps == maxCatchUp && !now.Before(l.next) {
+        l.next = now.Add(l.tick)
+    }
+}
+
+// step advances the game by one tick, unless it is paused
+func (l *gameLoop) step() {
+    var inp string
+    if l.replay != nil {
+        inp = l.replay.Input(l.game.Tick() + 1)
+    } else if len(l.pending) > 0 {
+        inp = l.pending[0]
+    }
+
+    // time stands still while the game is paused or cannot be seen, but
+    // the player can still quit
+    if (l.menu != nil || (l.hold != nil && l.hold())) && inp != "ESC" {
+        return
+    }
+    if l.replay == nil && len(l.pending) > 0 {
+        l.pending = l.pending[1:]
+    }
+
+    l.game.Step(inp)
+    if l.recording != nil {
+        l.recording.Record(l.game.Tick(), inp)
+    }
+}
+
+// done reports whether the game is over, or the replay being played has
+// reached the end of its recording
+func (l *gameLoop) done() bool {
+    if l.replay != nil && l.replay.FinalTick > 0 && l.game.Tick() >= l.replay.FinalTick {
+        return true
+    }
+    return l.game.IsOver()
+}
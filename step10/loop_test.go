@@ -0,0 +1,269 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "math/rand"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/hd2yao/pac-man/game"
+    "github.com/hd2yao/pac-man/input"
+)
+
+// fakeKeys is a key source fed from a channel instead of a terminal
+type fakeKeys struct {
+    data    chan []byte
+    pending []byte
+}
+
+func (f *fakeKeys) WaitForInput(timeout time.Duration) (bool, error) {
+    if len(f.pending) > 0 {
+        return true, nil
+    }
+    select {
+    case b := <-f.data:
+        f.pending = b
+        return true, nil
+    case <-time.After(timeout):
+        return false, nil
+    }
+}
+
+func (f *fakeKeys) Read(p []byte) (int, error) {
+    n := copy(p, f.pending)
+    f.pending = f.pending[n:]
+    return n, nil
+}
+
+func newTestGame(t *testing.T, seed int64) *game.Game {
+    t.Helper()
+    cfg, err := game.LoadConfig("config_noemoji.json")
+    if err != nil {
+        t.Fatal(err)
+    }
+    levels, err := game.LoadLevels("levels.json")
+    if err != nil {
+        t.Fatal(err)
+    }
+    return game.New(cfg, levels, seed)
+}
+
+func testBindings(t *testing.T) input.Bindings {
+    t.Helper()
+    bindings, err := input.NewBindings(nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return bindings
+}
+
+func TestReadInputStopsOnCancel(t *testing.T) {
+    ctx, cancel := context.WithCancel(context.Background())
+    keys := make(chan input.Key)
+    go readInput(ctx, &fakeKeys{data: make(chan []byte)}, keys)
+
+    cancel()
+    select {
+    case _, ok := <-keys:
+        if ok {
+            t.Fatal("got a key, want the channel closed")
+        }
+    case <-time.After(time.Second):
+        t.Fatal("input reader still running after cancel")
+    }
+}
+
+func TestReadInputStopsWhileBlockedSending(t *testing.T) {
+    ctx, cancel := context.WithCancel(context.Background())
+    src := &fakeKeys{data: make(chan []byte, 1)}
+    src.data <- []byte("aaaa")
+    keys := make(chan input.Key)
+    done := make(chan struct{})
+    go func() {
+        readInput(ctx, src, keys)
+        close(done)
+    }()
+
+    // nobody reads the keys, so the reader is stuck sending the first one
+    time.Sleep(10 * time.Millisecond)
+    cancel()
+    select {
+    case <-done:
+    case <-time.After(time.Second):
+        t.Fatal("input reader still running after cancel")
+    }
+}
+
+func TestGameLoopStopsOnCancel(t *testing.T) {
+    ctx, cancel := context.WithCancel(context.Background())
+    loop := &gameLoop{
+        game:     newTestGame(t, 1),
+        bindings: testBindings(t),
+        clock:    make(chan time.Time),
+        draw:     func(game.Snapshot, []string) error { return nil },
+    }
+
+    errc := make(chan error, 1)
+    go func() { errc <- loop.run(ctx) }()
+    cancel()
+
+    select {
+    case err := <-errc:
+        if !errors.Is(err, context.Canceled) {
+            t.Fatalf("run returned %v, want %v", err, context.Canceled)
+        }
+    case <-time.After(time.Second):
+        t.Fatal("game loop still running after cancel")
+    }
+}
+
+func TestGameLoopPauseFreezesTime(t *testing.T) {
+    clock := make(chan time.Time)
+    keys := make(chan input.Key)
+    loop := &gameLoop{
+        game:     newTestGame(t, 1),
+        bindings: testBindings(t),
+        keys:     keys,
+        clock:    clock,
+        draw:     func(game.Snapshot, []string) error { return nil },
+    }
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    errc := make(chan error, 1)
+    go func() { errc <- loop.run(ctx) }()
+
+    keys <- input.Key{Code: input.KeyRune, Rune: 'p'}
+    for i := 0; i < 10; i++ {
+        clock <- time.Now()
+    }
+    keys <- input.Key{Code: input.KeyRune, Rune: 'p'}
+    clock <- time.Now()
+    clock <- time.Now()
+    cancel()
+    <-errc
+
+    if tick := loop.game.Tick(); tick != 2 {
+        t.Fatalf("tick = %d after pausing for 10 ticks, want 2", tick)
+    }
+}
+
+func TestGameLoopCatchesUpWithLateClock(t *testing.T) {
+    const tick = 50 * time.Millisecond
+    clock := make(chan time.Time)
+    loop := &gameLoop{
+        game:     newTestGame(t, 1),
+        bindings: testBindings(t),
+        clock:    clock,
+        tick:     tick,
+        draw:     func(game.Snapshot, []string) error { return nil },
+    }
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    errc := make(chan error, 1)
+    go func() { errc <- loop.run(ctx) }()
+
+    start := time.Now()
+    for _, at := range []time.Duration{
+        0,
+        // two clock messages were dropped
+        3 * tick,
+        // the clock stalled for a long time, only maxCatchUp ticks are run
+        100 * tick,
+        // back on time after the stall
+        101 * tick,
+    } {
+        clock <- start.Add(at)
+    }
+    cancel()
+    <-errc
+
+    if want := 1 + 3 + maxCatchUp + 1; loop.game.Tick() != want {
+        t.Fatalf("tick = %d, want %d", loop.game.Tick(), want)
+    }
+}
+
+// TestGameLoopStress runs many games at once, each fed by its own clock and
+// key reader, and cancels them at random points. Run it with -race.
+func TestGameLoopStress(t *testing.T) {
+    const games = 20
+    keyBytes := [][]byte{
+        []byte("\x1b[A"), []byte("\x1b[B"), []byte("\x1b[C"), []byte("\x1b[D"),
+        []byte("w"), []byte("a"), []byte("s"), []byte("d"), []byte("p"), []byte("\x1b"),
+    }
+
+    bindings := testBindings(t)
+    var wg sync.WaitGroup
+    for i := 0; i < games; i++ {
+        g := newTestGame(t, int64(i))
+        wg.Add(1)
+        go func(seed int64) {
+            defer wg.Done()
+            rng := rand.New(rand.NewSource(seed))
+            ctx, cancel := context.WithTimeout(context.Background(), time.Duration(rng.Intn(200))*time.Millisecond)
+            defer cancel()
+
+            src := &fakeKeys{data: make(chan []byte)}
+            keys := make(chan input.Key, inputBuffer)
+            go readInput(ctx, src, keys)
+
+            // the player types at random
+            go func() {
+                for ctx.Err() == nil {
+                    select {
+                    case src.data <- keyBytes[rng.Intn(len(keyBytes))]:
+                    case <-ctx.Done():
+                    }
+                    time.Sleep(time.Millisecond)
+                }
+            }()
+
+            // the clock runs as fast as the loop takes ticks
+            clock := make(chan time.Time)
+            go func() {
+                for {
+                    select {
+                    case clock <- time.Now():
+                    case <-ctx.Done():
+                        return
+                    }
+                }
+            }()
+
+            // frames are looked at from another goroutine
+            frames := make(chan game.Snapshot, 1)
+            go func() {
+                for s := range frames {
+                    _ = s.Score + len(s.Ghosts) + len(s.Maze)
+                }
+            }()
+            defer close(frames)
+
+            loop := &gameLoop{
+                game:      g,
+                bindings:  bindings,
+                keys:      keys,
+                clock:     clock,
+                recording: &game.Replay{Seed: seed},
+                draw: func(s game.Snapshot, menu []string) error {
+                    select {
+                    case frames <- s:
+                    default:
+                    }
+                    return nil
+                },
+            }
+            err := loop.run(ctx)
+            if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+                t.Errorf("game %d: run returned %v", seed, err)
+            }
+
+            // the input reader must stop once the game is cancelled
+            cancel()
+            for range keys {
+            }
+        }(int64(i))
+    }
+    wg.Wait()
+}
@@ -1,400 +1,412 @@
 package main
 
 import (
-    "bufio"
     "bytes"
-    "encoding/json"
+    "context"
     "flag"
     "fmt"
     "log"
-    "math/rand"
     "os"
-    "os/exec"
     "strconv"
-    "sync"
     "time"
 
-    "github.com/danicat/simpleansi"
+    "github.com/hd2yao/pac-man/game"
+    "github.com/hd2yao/pac-man/input"
+    "github.com/hd2yao/pac-man/render"
+    "github.com/hd2yao/pac-man/terminal"
 )
 
 var (
     configFile = flag.String("config-file", "config.json", "path to custom configuration file")
     mazeFile   = flag.String("maze-flag", "maze01.txt", "path to custom maze file")
+    scoresFile = flag.String("highscores", "", "path to the high score table (default: in the user's config directory)")
+    levelsFile = flag.String("levels-file", "levels.json", "path to the level list; empty to play a single level on maze-flag")
+    seed       = flag.Int64("seed", 0, "random seed for reproducible runs (default: based on the current time)")
+    recordFile = flag.String("record", "", "record the game's inputs to a replay file")
+    replayFile = flag.String("replay", "", "re-run a game from a replay file instead of reading the keyboard")
+    headless   = flag.Bool("headless", false, "run without a terminal at full speed and print a JSON summary")
+    scriptFile = flag.String("script", "", "input script for headless mode (default: read from stdin)")
+    maxTicks   = flag.Int("max-ticks", 100000, "stop a headless game after this many ticks")
+    speed      = flag.Float64("speed", 1, "simulation speed multiplier, e.g. 2 runs twice as fast as real time")
 )
 
-// Config holds the emoji configuration
-type Config struct {
-    Player           string        `json:"player"`
-    Ghost            string        `json:"ghost"`
-    Wall             string        `json:"wall"`
-    Dot              string        `json:"dot"`
-    Pill             string        `json:"pill"`
-    Death            string        `json:"death"`
-    Space            string        `json:"space"`
-    UseEmoji         bool          `json:"use_emoji"`
-    GhostBlue        string        `json:"ghost_blue"`
-    PillDurationSecs time.Duration `json:"pill_duration_secs"`
-}
-
-var cfg Config
-
-func loadConfig(file string) error {
-    f, err := os.Open(file)
-    if err != nil {
-        return err
-    }
-    defer f.Close()
-
-    decoder := json.NewDecoder(f)
-    err = decoder.Decode(&cfg)
-    if err != nil {
-        return err
-    }
-
-    return nil
-}
-
-type GhostStatus string
-
-const (
-    GhostStatusNormal GhostStatus = "Normal"
-    GhostStatusBlue   GhostStatus = "Blue"
-)
-
-// define sprite struct to tracking 2D coordinates(row and column) information
-type sprite struct {
-    row      int
-    col      int
-    startRow int
-    startCol int
-}
-
-type ghost struct {
-    position sprite
-    status   GhostStatus
-}
-
-var player sprite
-var ghosts []*ghost
-var maze []string
-var score int
-var numDots int
-var lives = 3
-
-func loadMaze(file string) error {
-    f, err := os.Open(file)
-    if err != nil {
-        return err
-    }
-    defer f.Close()
+var cfg game.Config
 
-    scanner := bufio.NewScanner(f)
-    for scanner.Scan() {
-        line := scanner.Text()
-        maze = append(maze, line)
+// buildFrame lays out the maze, the sprites and the status lines of a
+// snapshot, with the pause menu over the maze when menu is not nil
+func buildFrame(glyphs *glyphSet, s game.Snapshot, highScore int, menu []string) *render.Frame {
+    cols := 0
+    if len(s.Maze) > 0 {
+        cols = len(s.Maze[0])
     }
+    f := render.NewFrame(len(s.Maze), cols)
 
-    // traverse each character of the maze
-    for row, line := range maze {
+    for row, line := range s.Maze {
         for col, char := range line {
-            switch char {
-            case 'P':
-                player = sprite{row, col, row, col}
-            case 'G':
-                ghosts = append(ghosts, &ghost{sprite{row, col, row, col}, GhostStatusNormal})
-            case '.':
-                numDots++
-            }
-        }
-    }
-
-    return nil
-}
-
-func printScreen() {
-    simpleansi.ClearScreen()
-    for _, line := range maze {
-        for _, char := range line {
             switch char {
             case '#':
-                fmt.Print(simpleansi.WithBlueBackground(cfg.Wall))
+                f.Set(row, col, glyphs.wall)
             case '.':
-                fmt.Print(cfg.Dot)
+                f.Set(row, col, glyphs.dot)
             case 'X':
-                fmt.Print(cfg.Pill)
+                f.Set(row, col, glyphs.pill)
             default:
-                fmt.Print(cfg.Space)
+                f.Set(row, col, glyphs.space)
             }
         }
-        fmt.Println()
     }
 
-    moveCursor(player.row, player.col)
-    fmt.Print(cfg.Player)
+    if s.Fruit != nil {
+        f.Set(s.Fruit.Row, s.Fruit.Col, glyphs.fruits[s.Fruit.Name])
+    }
+
+    f.Set(s.Player.Row, s.Player.Col, glyphs.player)
+    f.SetFocus(s.Player.Row, s.Player.Col)
 
-    for _, ghost := range ghosts {
-        moveCursor(ghost.position.row, ghost.position.col)
-        if ghost.status == GhostStatusNormal {
-            fmt.Print(cfg.Ghost)
-        } else if ghost.status == GhostStatusBlue {
-            fmt.Print(cfg.GhostBlue)
+    // the ghosts are out of sight while the death animation plays
+    animating := s.Dying && s.DeathAnimation > 0
+    for _, ghost := range s.Ghosts {
+        if animating {
+            break
+        }
+        if ghost.Status == game.GhostStatusNormal {
+            f.Set(ghost.Row, ghost.Col, glyphs.ghost)
+        } else if ghost.Status == game.GhostStatusBlue {
+            f.Set(ghost.Row, ghost.Col, glyphs.ghostBlue)
+        } else if ghost.Status == game.GhostStatusEyes {
+            f.Set(ghost.Row, ghost.Col, glyphs.ghostEyes)
         }
     }
 
-    // 将光标移出迷宫绘图区域
-    moveCursor(len(maze)+1, 0)
+    if animating {
+        f.Set(s.Player.Row, s.Player.Col, glyphs.deathFrame(s.DeathAnimation))
+    } else if s.Lives <= 0 {
+        f.Set(s.Player.Row, s.Player.Col, glyphs.death)
+    }
 
-    livesRemaining := strconv.Itoa(lives) //converts lives int to a string
+    livesRemaining := strconv.Itoa(s.Lives) //converts lives int to a string
     if cfg.UseEmoji {
-        livesRemaining = getLivesAsEmoji()
+        livesRemaining = getLivesAsEmoji(s.Lives)
+    }
+    if s.ExtraLife {
+        livesRemaining += " 1UP!"
     }
 
-    fmt.Println("Score:", score, "\tLives:", livesRemaining)
-}
+    if s.Score > highScore {
+        highScore = s.Score
+    }
+    f.AddLine(fmt.Sprintf("Score: %d    High: %d    Lives: %s    Level: %d", s.Score, highScore, livesRemaining, s.Level))
 
-func initialise() {
-    cbTerm := exec.Command("stty", "cbreak", "-echo")
-    cbTerm.Stdin = os.Stdin
+    // collected fruit row
+    buf := bytes.Buffer{}
+    for _, fruit := range s.Collected {
+        buf.WriteString(cfg.Fruits[fruit])
+    }
+    f.AddLine(buf.String())
 
-    err := cbTerm.Run()
-    if err != nil {
-        log.Fatalln("unable to activate cbreak mode:", err)
+    if s.Ready {
+        f.AddLine("READY!")
     }
+    if s.Intermission {
+        f.AddLine(fmt.Sprint("LEVEL ", s.Level+1))
+    }
+    if menu != nil {
+        f.SetOverlay(menu...)
+    }
+    return f
 }
 
-func getLivesAsEmoji() string {
+// maxLivesShown is the number of player icons drawn in the HUD, more lives
+// than that are shown as a count
+const maxLivesShown = 5
+
+func getLivesAsEmoji(lives int) string {
     buf := bytes.Buffer{}
-    for i := lives; i > 0; i-- {
+    for i := 0; i < lives && i < maxLivesShown; i++ {
         buf.WriteString(cfg.Player)
     }
+    if lives > maxLivesShown {
+        fmt.Fprintf(&buf, "x%d", lives)
+    }
     return buf.String()
 }
 
-func cleanup() {
-    cookedTerm := exec.Command("stty", "-cbreak", "echo")
-    cookedTerm.Stdin = os.Stdin
-
-    err := cookedTerm.Run()
+// resizeScreen lets screen know the current size of the terminal
+func resizeScreen(term *terminal.Terminal, screen *render.Renderer) {
+    rows, cols, err := term.Size()
     if err != nil {
-        log.Fatalln("unable to restore cooked mode:", err)
+        // draw from the top left corner without scrolling
+        rows, cols = 0, 0
     }
+    screen.Resize(rows, cols)
 }
 
-func readInput() (string, error) {
-    buffer := make([]byte, 100)
-
-    cnt, err := os.Stdin.Read(buffer)
-    if err != nil {
-        return "", err
-    }
-
-    if cnt == 1 && buffer[0] == 0x1b {
-        return "ESC", nil
-    } else if cnt >= 3 { // 方向键的转义序列有 3 个字节长
-        // 以 ESC+[ 开头，然后是 A~D 之间的字母
-        if buffer[0] == 0x1b && buffer[1] == '[' {
-            switch buffer[2] {
-            case 'A':
-                return "UP", nil
-            case 'B':
-                return "DOWN", nil
-            case 'C':
-                return "RIGHT", nil
-            case 'D':
-                return "LEFT", nil
-            }
+// isFlagSet reports whether a flag was given on the command line
+func isFlagSet(name string) bool {
+    set := false
+    flag.Visit(func(f *flag.Flag) {
+        if f.Name == name {
+            set = true
         }
-    }
-
-    return "", nil
+    })
+    return set
 }
 
-func makeMove(oldRow, oldCol int, dir string) (newRow, newCol int) {
-    newRow, newCol = oldRow, oldCol
-
-    switch dir {
-    case "UP":
-        newRow = newRow - 1
-        if newRow < 0 {
-            // 再次回到最下面一行
-            newRow = len(maze) - 1
-        }
-    case "DOWN":
-        newRow = newRow + 1
-        if newRow == len(maze) {
-            newRow = 0
-        }
-    case "RIGHT":
-        newCol = newCol + 1
-        if newCol == len(maze[0]) {
-            newCol = 0
-        }
-    case "LEFT":
-        newCol = newCol - 1
-        if newCol < 0 {
-            newCol = len(maze[0]) - 1
-        }
+// levelSource returns the level list to play, or an empty string and the
+// maze file to play a single level on. Giving only maze-flag on the command
+// line plays that maze on its own.
+func levelSource() (levels string, maze string) {
+    if isFlagSet("maze-flag") && !isFlagSet("levels-file") {
+        return "", *mazeFile
     }
+    return *levelsFile, *mazeFile
+}
 
-    // 先尝试移动，如果新的位置碰巧遇到墙（#），则移动呗取消
-    if maze[newRow][newCol] == '#' {
-        newRow = oldRow
-        newCol = oldCol
+// loadLevels loads a level list, or a single level from a maze file when no
+// level list is given
+func loadLevels(levelsPath, mazePath string) ([]game.Level, error) {
+    if levelsPath != "" {
+        return game.LoadLevels(levelsPath)
     }
 
-    return
+    maze, err := game.LoadMaze(mazePath)
+    if err != nil {
+        return nil, err
+    }
+    return game.SingleLevel(mazePath, maze), nil
 }
 
-func movePlayer(dir string) {
-    player.row, player.col = makeMove(player.row, player.col, dir)
-
-    // Remove dot from maze
-    removeDot := func(row, col int) {
-        maze[row] = maze[row][0:col] + " " + maze[row][col+1:]
+// loadReplay reads a replay file and the levels it was recorded on, making
+// sure they have not changed since
+func loadReplay(file string) (*game.Replay, []game.Level, error) {
+    replay, err := game.LoadReplay(file)
+    if err != nil {
+        return nil, nil, err
     }
 
-    switch maze[player.row][player.col] {
-    case '.':
-        numDots--
-        score++
-        removeDot(player.row, player.col)
-    case 'X':
-        score += 10
-        removeDot(player.row, player.col)
-        go processPill()
+    levelsPath, mazePath := replay.LevelsFile, replay.MazeFile
+    if isFlagSet("levels-file") || isFlagSet("maze-flag") {
+        levelsPath, mazePath = levelSource()
+    }
+    levels, err := loadLevels(levelsPath, mazePath)
+    if err != nil {
+        return nil, nil, err
+    }
+    if game.HashLevels(levels) != replay.MazeHash {
+        return nil, nil, fmt.Errorf("levels do not match the ones the replay was recorded on")
     }
-}
 
-var pillTimer *time.Timer
-var pillMx sync.Mutex
-
-func processPill() {
-    pillMx.Lock()
-    updateGhosts(ghosts, GhostStatusBlue)
-    if pillTimer != nil {
-        pillTimer.Stop()
-    }
-    pillTimer = time.NewTimer(time.Second * cfg.PillDurationSecs)
-    pillMx.Unlock()
-    <-pillTimer.C
-    pillMx.Lock()
-    pillTimer.Stop()
-    updateGhosts(ghosts, GhostStatusNormal)
-    pillMx.Unlock()
+    return replay, levels, nil
 }
 
-var ghostsStatusMx sync.RWMutex
-
-func updateGhosts(ghosts []*ghost, ghostStatus GhostStatus) {
-    ghostsStatusMx.Lock()
-    defer ghostsStatusMx.Unlock()
-    for _, ghost := range ghosts {
-        ghost.status = ghostStatus
+func main() {
+    if len(os.Args) > 1 && os.Args[1] == "validate" {
+        os.Exit(runValidate(os.Args[2:]))
     }
-}
 
-func drawDirection() string {
-    dir := rand.Intn(4)
-    move := map[int]string{
-        0: "UP",
-        1: "DOWN",
-        2: "RIGHT",
-        3: "LEFT",
+    flag.Usage = func() {
+        out := flag.CommandLine.Output()
+        fmt.Fprintf(out, "usage: %s [flags]\n       %s validate FILE...\n\nflags:\n", os.Args[0], os.Args[0])
+        flag.PrintDefaults()
     }
-    return move[dir]
-}
-
-func moveGhosts() {
-    for _, ghost := range ghosts {
-        dir := drawDirection()
-        ghost.position.row, ghost.position.col = makeMove(ghost.position.row, ghost.position.col, dir)
+    flag.Parse()
+    if *speed <= 0 {
+        log.Println("invalid speed:", *speed)
+        return
     }
-}
 
-func moveCursor(row, col int) {
-    if cfg.UseEmoji {
-        // 将 col 值缩放2倍，确保每个角色都定位在正确的位置，不过会让迷宫看起来更大
-        simpleansi.MoveCursor(row, col*2)
+    // load resources
+    var levels []game.Level
+    var replay *game.Replay
+    var err error
+    levelsPath, mazePath := levelSource()
+    if *replayFile != "" {
+        replay, levels, err = loadReplay(*replayFile)
+        if err != nil {
+            log.Println("failed to load replay:", err)
+            return
+        }
+        cfg, *seed = replay.Config, replay.Seed
     } else {
-        simpleansi.MoveCursor(row, col)
+        levels, err = loadLevels(levelsPath, mazePath)
+        if err != nil {
+            log.Println("failed to load maze:", err)
+            return
+        }
+
+        cfg, err = game.LoadConfig(*configFile)
+        if err != nil {
+            log.Println("failed to load configuration:", err)
+            return
+        }
+
+        // only use a time based seed if none was given, so that 0 is a valid seed
+        if !isFlagSet("seed") {
+            *seed = time.Now().UnixNano()
+        }
     }
-}
 
-func main() {
-    flag.Parse()
+    bindings, err := input.NewBindings(cfg.Keys)
+    if err != nil {
+        log.Println("invalid key bindings:", err)
+        return
+    }
 
-    // initialize game
-    initialise()
-    defer cleanup()
+    recording := newRecording(levelsPath, mazePath, levels)
 
-    // load resources
-    err := loadMaze(*mazeFile)
+    g := game.New(cfg, levels, *seed)
+
+    if *headless {
+        inputAt, err := headlessInput(replay)
+        if err != nil {
+            log.Println("failed to load script:", err)
+            return
+        }
+        err = runHeadless(os.Stdout, g, inputAt, recording)
+        if err != nil {
+            log.Println("headless run failed:", err)
+        }
+        saveRecording(g, recording)
+        return
+    }
+
+    glyphs, err := loadGlyphs(cfg)
     if err != nil {
-        log.Println("failed to load maze:", err)
+        log.Println(err)
         return
     }
 
-    err = loadConfig(*configFile)
+    scoresPath, scores := loadHighScores()
+
+    // initialize game
+    term, err := terminal.Open(os.Stdin, os.Stdout)
     if err != nil {
-        log.Println("failed to load configuration:", err)
+        log.Println("unable to activate cbreak mode:", err)
         return
     }
+    defer term.Restore()
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    term.RestoreOnSignal(ctx)
 
     // process input (async)
-    input := make(chan string)
-    go func(ch chan<- string) {
-        for {
-            input, err := readInput()
-            if err != nil {
-                log.Print("error reading input:", err)
-                ch <- "ESC"
-            }
-            ch <- input
-        }
-    }(input)
-
-    // game loop
-    for {
-        // process movement
-        select {
-        case inp := <-input:
-            if inp == "ESC" {
-                lives = 0
-            }
-            movePlayer(inp)
-        default:
+    var keys chan input.Key
+    if replay == nil {
+        keys = make(chan input.Key, inputBuffer)
+        go func() {
+            defer term.RestoreOnPanic()
+            readInput(ctx, term, keys)
+        }()
+    }
+
+    tick := tickDuration(*speed)
+    clock := time.NewTicker(tick)
+    defer clock.Stop()
+
+    screen := render.New(os.Stdout, glyphs.width)
+    resizeScreen(term, screen)
+
+    loop := &gameLoop{
+        game:      g,
+        bindings:  bindings,
+        keys:      keys,
+        clock:     clock.C,
+        tick:      tick,
+        resized:   term.NotifyResize(ctx),
+        replay:    replay,
+        recording: recording,
+        draw: func(s game.Snapshot, menu []string) error {
+            return screen.Draw(buildFrame(glyphs, s, scores.High(), menu))
+        },
+        resize: func() {
+            resizeScreen(term, screen)
+        },
+        hold:     screen.TooSmall,
+        settings: settings{speed: *speed, sound: true},
+    }
+    loop.setSpeed = func(speed float64) {
+        // the next tick was due at the old speed, start again from the
+        // next clock message
+        loop.tick = tickDuration(speed)
+        loop.next = time.Time{}
+        clock.Reset(loop.tick)
+    }
+    ringBell(loop, g)
+    loop.restart = func() (*game.Game, *game.Replay) {
+        if !isFlagSet("seed") {
+            *seed = time.Now().UnixNano()
         }
+        g := game.New(cfg, levels, *seed)
+        ringBell(loop, g)
+        return g, newRecording(levelsPath, mazePath, levels)
+    }
 
-        moveGhosts()
-
-        // process collisions
-        for _, ghost := range ghosts {
-            if player.row == ghost.position.row && player.col == ghost.position.col {
-                lives--
-                if lives != 0 {
-                    moveCursor(player.row, player.col)
-                    fmt.Print(cfg.Death)
-                    moveCursor(len(maze)+2, 0)
-                    time.Sleep(1000 * time.Millisecond) //dramatic pause before resetting player position
-                    player.row, player.col = player.startRow, player.startCol
-                }
-            }
+    err = loop.run(ctx)
+    if err != nil {
+        log.Println("failed to draw screen:", err)
+        return
+    }
+    g, recording = loop.game, loop.recording
+    fmt.Println("Game over! Seed:", g.Seed())
+
+    if replay != nil {
+        fmt.Println("Replay finished with score", g.Snapshot().Score, "- recorded score was", replay.FinalScore)
+    } else if scoresPath != "" {
+        drainInput(keys)
+        recordHighScore(g, scores, scoresPath, keys, mazeName(levelsPath, mazePath))
+    }
+    if keys != nil {
+        // wait for the input reader to stop
+        cancel()
+        for range keys {
         }
+    }
 
-        // update screen
-        printScreen()
+    saveRecording(g, recording)
+}
 
-        // check game over
-        if numDots == 0 || lives <= 0 {
-            if lives == 0 {
-                moveCursor(player.row, player.col)
-                fmt.Print(cfg.Death)
-                moveCursor(len(maze)+2, 0)
-            }
-            break
+// tickDuration is the real time taken by a tick when the game runs at
+// speed
+func tickDuration(speed float64) time.Duration {
+    return time.Duration(float64(game.TickDuration) / speed)
+}
+
+// ringBell rings the terminal bell whenever g grants an extra life, unless
+// the sound was turned off in the pause menu
+func ringBell(loop *gameLoop, g *game.Game) {
+    game.On(g, func(game.ExtraLifeEarned) {
+        if loop.settings.sound {
+            fmt.Print("\a")
         }
+    })
+}
 
-        // repeat
-        time.Sleep(200 * time.Millisecond)
+// newRecording starts recording a game played with the current seed and
+// configuration, if a recording was asked for
+func newRecording(levelsPath, mazePath string, levels []game.Level) *game.Replay {
+    if *recordFile == "" {
+        return nil
+    }
+    return &game.Replay{
+        Seed:       *seed,
+        LevelsFile: levelsPath,
+        MazeFile:   mazePath,
+        MazeHash:   game.HashLevels(levels),
+        Config:     cfg,
+    }
+}
+
+// saveRecording writes the recorded inputs of a finished game, if the game
+// was being recorded
+func saveRecording(g *game.Game, recording *game.Replay) {
+    if recording == nil {
+        return
+    }
+
+    recording.Finish(g)
+    err := game.SaveReplay(*recordFile, recording)
+    if err != nil {
+        log.Println("failed to save replay:", err)
     }
 }
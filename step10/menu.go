@@ -0,0 +1,135 @@
+package main
+
+import (
+    "fmt"
+
+    "github.com/hd2yao/pac-man/input"
+)
+
+// speeds the options menu cycles through
+var menuSpeeds = []float64{0.5, 1, 1.5, 2}
+
+// settings are the options that can be changed from the pause menu
+type settings struct {
+    speed float64
+    sound bool
+}
+
+// nextSpeed returns the speed after s.speed in menuSpeeds, going back to
+// the first one after the last
+func (s settings) nextSpeed() float64 {
+    for _, speed := range menuSpeeds {
+        if speed > s.speed {
+            return speed
+        }
+    }
+    return menuSpeeds[0]
+}
+
+// menuChoice is what the player picked in the pause menu
+type menuChoice int
+
+const (
+    choiceNone menuChoice = iota
+    choiceResume
+    choiceRestart
+    choiceQuit
+)
+
+var (
+    mainMenu    = []string{"Resume", "Restart", "Options", "Quit"}
+    optionsMenu = []string{"Speed", "Sound", "Back"}
+)
+
+// optionsEntry is where "Options" is in mainMenu, selected when coming back
+// from the options
+const optionsEntry = 2
+
+// pauseMenu is the menu shown over the maze while the game is paused
+type pauseMenu struct {
+    options  bool
+    selected int
+}
+
+// items returns the entries of the menu being shown
+func (m *pauseMenu) items() []string {
+    if m.options {
+        return optionsMenu
+    }
+    return mainMenu
+}
+
+// handle acts on a key press: up and down move through the entries, enter
+// picks one, left and right change an option, and quit goes back. Leaving
+// the menu with the pause key or quit resumes the game.
+func (m *pauseMenu) handle(key input.Key, bindings input.Bindings, s *settings) menuChoice {
+    action, _ := bindings.Action(key)
+    items := m.items()
+
+    switch {
+    case action == input.ActionUp:
+        m.selected = (m.selected + len(items) - 1) % len(items)
+    case action == input.ActionDown:
+        m.selected = (m.selected + 1) % len(items)
+    case action == input.ActionPause:
+        return choiceResume
+    case action == input.ActionQuit:
+        if !m.options {
+            return choiceResume
+        }
+        m.options, m.selected = false, optionsEntry
+    case key.Code == input.KeyEnter || (m.options && (action == input.ActionLeft || action == input.ActionRight)):
+        return m.pick(s)
+    }
+    return choiceNone
+}
+
+// pick acts on the selected entry
+func (m *pauseMenu) pick(s *settings) menuChoice {
+    if m.options {
+        switch optionsMenu[m.selected] {
+        case "Speed":
+            s.speed = s.nextSpeed()
+        case "Sound":
+            s.sound = !s.sound
+        case "Back":
+            m.options, m.selected = false, optionsEntry
+        }
+        return choiceNone
+    }
+
+    switch mainMenu[m.selected] {
+    case "Resume":
+        return choiceResume
+    case "Restart":
+        return choiceRestart
+    case "Options":
+        m.options, m.selected = true, 0
+    case "Quit":
+        return choiceQuit
+    }
+    return choiceNone
+}
+
+// lines returns the text of the menu, with the selected entry marked
+func (m *pauseMenu) lines(s settings) []string {
+    lines := []string{"PAUSED", ""}
+    for i, item := range m.items() {
+        switch item {
+        case "Speed":
+            item = fmt.Sprintf("Speed: %gx", s.speed)
+        case "Sound":
+            item = "Sound: off"
+            if s.sound {
+                item = "Sound: on"
+            }
+        }
+
+        marker := "  "
+        if i == m.selected {
+            marker = "> "
+        }
+        lines = append(lines, marker+item)
+    }
+    return lines
+}
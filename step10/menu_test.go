@@ -0,0 +1,206 @@
+package main
+
+import (
+    "context"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/hd2yao/pac-man/game"
+    "github.com/hd2yao/pac-man/input"
+)
+
+var (
+    keyPause = input.Key{Code: input.KeyRune, Rune: 'p'}
+    keyUp    = input.Key{Code: input.KeyUp}
+    keyDown  = input.Key{Code: input.KeyDown}
+    keyRight = input.Key{Code: input.KeyRight}
+    keyLeft  = input.Key{Code: input.KeyLeft}
+    keyEnter = input.Key{Code: input.KeyEnter}
+    keyEsc   = input.Key{Code: input.KeyEsc}
+)
+
+// runLoop starts l on its own goroutine with unbuffered key and clock
+// channels, so that every send returns once the loop has taken the message.
+// The returned function stops the loop and waits for it.
+func runLoop(t *testing.T, l *gameLoop) (keys chan<- input.Key, clock chan<- time.Time, stop func()) {
+    t.Helper()
+    k := make(chan input.Key)
+    c := make(chan time.Time)
+    l.keys, l.clock = k, c
+    if l.draw == nil {
+        l.draw = func(game.Snapshot, []string) error { return nil }
+    }
+
+    ctx, cancel := context.WithCancel(context.Background())
+    errc := make(chan error, 1)
+    go func() { errc <- l.run(ctx) }()
+    return k, c, func() {
+        cancel()
+        <-errc
+    }
+}
+
+func TestMenuRestartStartsNewGameAndRecording(t *testing.T) {
+    first, second := newTestGame(t, 1), newTestGame(t, 2)
+    firstRec, secondRec := &game.Replay{Seed: 1}, &game.Replay{Seed: 2}
+    restarts := 0
+    loop := &gameLoop{
+        game:      first,
+        bindings:  testBindings(t),
+        recording: firstRec,
+        restart: func() (*game.Game, *game.Replay) {
+            restarts++
+            return second, secondRec
+        },
+    }
+    keys, clock, stop := runLoop(t, loop)
+
+    keys <- keyLeft
+    clock <- time.Now()
+    keys <- keyPause
+    keys <- keyDown
+    keys <- keyEnter
+    keys <- keyRight
+    clock <- time.Now()
+    stop()
+
+    if restarts != 1 || loop.game != second || loop.recording != secondRec {
+        t.Fatalf("restarted %d times, playing the new game %v with the new recording %v", restarts, loop.game == second, loop.recording == secondRec)
+    }
+    if loop.menu != nil {
+        t.Errorf("menu still open after restarting")
+    }
+    if first.Tick() != 1 || second.Tick() != 1 {
+        t.Errorf("ticks = %d, %d, want one tick in each game", first.Tick(), second.Tick())
+    }
+    if want := []game.InputEvent{{Tick: 1, Input: "LEFT"}}; !reflect.DeepEqual(firstRec.Inputs, want) {
+        t.Errorf("first recording = %v, want %v", firstRec.Inputs, want)
+    }
+    if want := []game.InputEvent{{Tick: 1, Input: "RIGHT"}}; !reflect.DeepEqual(secondRec.Inputs, want) {
+        t.Errorf("second recording = %v, want %v", secondRec.Inputs, want)
+    }
+}
+
+func TestMenuQuitEndsGame(t *testing.T) {
+    loop := &gameLoop{game: newTestGame(t, 1), bindings: testBindings(t)}
+    keys, clock, stop := runLoop(t, loop)
+
+    keys <- keyPause
+    // up from the first entry wraps around to Quit
+    keys <- keyUp
+    keys <- keyEnter
+    clock <- time.Now()
+    stop()
+
+    if loop.menu != nil {
+        t.Errorf("menu still open after quitting")
+    }
+    if !loop.game.IsOver() || loop.game.Tick() != 1 {
+        t.Errorf("game over = %v at tick %d, want it over at tick 1", loop.game.IsOver(), loop.game.Tick())
+    }
+}
+
+func TestMenuQuitKeyResumes(t *testing.T) {
+    loop := &gameLoop{game: newTestGame(t, 1), bindings: testBindings(t)}
+    keys, clock, stop := runLoop(t, loop)
+
+    // the quit key leaves the options, then the menu, without ending the
+    // game
+    keys <- keyPause
+    keys <- keyDown
+    keys <- keyDown
+    keys <- keyEnter
+    keys <- keyEsc
+    keys <- keyEsc
+    clock <- time.Now()
+    stop()
+
+    if loop.menu != nil || loop.game.IsOver() || loop.game.Tick() != 1 {
+        t.Errorf("menu open = %v, game over = %v, tick %d, want the game running at tick 1", loop.menu != nil, loop.game.IsOver(), loop.game.Tick())
+    }
+}
+
+func TestMenuSpeedCallsSetSpeed(t *testing.T) {
+    var speeds []float64
+    loop := &gameLoop{
+        game:     newTestGame(t, 1),
+        bindings: testBindings(t),
+        settings: settings{speed: 1, sound: true},
+        setSpeed: func(speed float64) { speeds = append(speeds, speed) },
+    }
+    keys, _, stop := runLoop(t, loop)
+
+    keys <- keyPause
+    keys <- keyDown
+    keys <- keyDown
+    keys <- keyEnter // Options
+    keys <- keyEnter // Speed: 1.5x
+    keys <- keyRight // 2x
+    keys <- keyLeft  // back round to 0.5x
+    keys <- keyDown
+    keys <- keyEnter // Sound: off, which is not a speed change
+    stop()
+
+    if want := []float64{1.5, 2, 0.5}; !reflect.DeepEqual(speeds, want) {
+        t.Errorf("setSpeed called with %v, want %v", speeds, want)
+    }
+    if want := (settings{speed: 0.5, sound: false}); loop.settings != want {
+        t.Errorf("settings = %+v, want %+v", loop.settings, want)
+    }
+}
+
+func TestResumeDropsBufferedKeys(t *testing.T) {
+    keys := make(chan input.Key, inputBuffer)
+    rec := &game.Replay{}
+    loop := &gameLoop{
+        game:      newTestGame(t, 1),
+        bindings:  testBindings(t),
+        keys:      keys,
+        recording: rec,
+    }
+
+    loop.queue(gameInput(loop.bindings, keyLeft))
+    loop.queue(gameInput(loop.bindings, keyPause))
+    if loop.menu == nil || len(loop.pending) != 0 {
+        t.Fatalf("menu open = %v with %d inputs pending, want it open with none", loop.menu != nil, len(loop.pending))
+    }
+
+    // keys typed while the menu is open that the loop has not read yet
+    keys <- keyUp
+    keys <- keyUp
+    keys <- keyRight
+    loop.menuKey(keyPause)
+
+    if loop.menu != nil {
+        t.Fatalf("menu still open after the pause key")
+    }
+    if len(keys) != 0 || len(loop.pending) != 0 {
+        t.Errorf("%d keys and %d inputs left after resuming, want none", len(keys), len(loop.pending))
+    }
+
+    loop.step()
+    if loop.game.Tick() != 1 || len(rec.Inputs) != 0 {
+        t.Errorf("tick %d with inputs %v, want one tick without input", loop.game.Tick(), rec.Inputs)
+    }
+}
+
+func TestResumeWithClosedKeys(t *testing.T) {
+    keys := make(chan input.Key)
+    loop := &gameLoop{
+        game:     newTestGame(t, 1),
+        bindings: testBindings(t),
+        keys:     keys,
+        menu:     &pauseMenu{},
+    }
+    close(keys)
+
+    loop.resume()
+    if loop.keys != nil || !reflect.DeepEqual(loop.pending, []string{"ESC"}) {
+        t.Fatalf("keys = %v, pending = %v, want no keys and ESC pending", loop.keys, loop.pending)
+    }
+    loop.step()
+    if !loop.game.IsOver() {
+        t.Errorf("game still running after the terminal went away")
+    }
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+
+    "github.com/hd2yao/pac-man/game"
+)
+
+// runValidate implements the validate subcommand: it checks maze files, or
+// every maze of a level list (a .json file), and reports all problems found.
+// It returns the process exit code.
+func runValidate(files []string) int {
+    if len(files) == 0 {
+        fmt.Fprintf(os.Stderr, "usage: %s validate FILE...\n", os.Args[0])
+        return 2
+    }
+
+    status := 0
+    for _, file := range files {
+        var err error
+        if filepath.Ext(file) == ".json" {
+            _, err = game.LoadLevels(file)
+        } else {
+            _, err = game.LoadMaze(file)
+        }
+
+        if err != nil {
+            fmt.Println(err)
+            status = 1
+            continue
+        }
+        fmt.Printf("%s: ok\n", file)
+    }
+
+    return status
+}
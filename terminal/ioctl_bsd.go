@@ -0,0 +1,10 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package terminal
+
+import "golang.org/x/sys/unix"
+
+const (
+    ioctlGetTermios = unix.TIOCGETA
+    ioctlSetTermios = unix.TIOCSETA
+)
@@ -0,0 +1,156 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+// Package terminal switches the terminal into the raw input mode used by the
+// game and makes sure it is always put back the way it was found.
+package terminal
+
+import (
+    "context"
+    "fmt"
+    "io"
+    "os"
+    "os/signal"
+    "sync"
+    "syscall"
+    "time"
+
+    "golang.org/x/sys/unix"
+)
+
+const (
+    hideCursor = "\x1b[?25l"
+    showCursor = "\x1b[?25h"
+)
+
+// Terminal is a terminal in cbreak mode: input is available byte by byte
+// as soon as it is typed and is not echoed back
+type Terminal struct {
+    in   *os.File
+    fd   int
+    out  io.Writer
+    orig unix.Termios
+
+    mu       sync.Mutex
+    restored bool
+}
+
+// Open puts the terminal attached to in into cbreak mode and hides the
+// cursor on out. Restore must be called to undo the changes.
+func Open(in *os.File, out io.Writer) (*Terminal, error) {
+    fd := int(in.Fd())
+    orig, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+    if err != nil {
+        return nil, fmt.Errorf("not a terminal: %w", err)
+    }
+
+    raw := *orig
+    raw.Lflag &^= unix.ICANON | unix.ECHO
+    raw.Cc[unix.VMIN] = 1
+    raw.Cc[unix.VTIME] = 0
+    err = unix.IoctlSetTermios(fd, ioctlSetTermios, &raw)
+    if err != nil {
+        return nil, err
+    }
+
+    fmt.Fprint(out, hideCursor)
+    return &Terminal{in: in, fd: fd, out: out, orig: *orig}, nil
+}
+
+// Read reads raw input from the terminal
+func (t *Terminal) Read(p []byte) (int, error) {
+    return t.in.Read(p)
+}
+
+// Restore puts the terminal back into the mode it was in before Open and
+// shows the cursor again. It is safe to call more than once, and from
+// several goroutines.
+func (t *Terminal) Restore() error {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    if t.restored {
+        return nil
+    }
+    t.restored = true
+
+    fmt.Fprint(t.out, showCursor)
+    return unix.IoctlSetTermios(t.fd, ioctlSetTermios, &t.orig)
+}
+
+// WaitForInput waits up to timeout for input to become available and
+// reports whether there is some
+func (t *Terminal) WaitForInput(timeout time.Duration) (bool, error) {
+    fds := []unix.PollFd{{Fd: int32(t.fd), Events: unix.POLLIN}}
+    for {
+        n, err := unix.Poll(fds, int(timeout/time.Millisecond))
+        if err == unix.EINTR {
+            continue
+        }
+        return n > 0, err
+    }
+}
+
+// Size returns the number of rows and columns of the terminal
+func (t *Terminal) Size() (rows, cols int, err error) {
+    ws, err := unix.IoctlGetWinsize(t.fd, unix.TIOCGWINSZ)
+    if err != nil {
+        return 0, 0, err
+    }
+    return int(ws.Row), int(ws.Col), nil
+}
+
+// NotifyResize returns a channel that receives a value whenever the
+// terminal is resized. Resizes that happen before the last one was received
+// are merged into one. Resizes are watched until ctx is done.
+func (t *Terminal) NotifyResize(ctx context.Context) <-chan struct{} {
+    sigs := make(chan os.Signal, 1)
+    ch := make(chan struct{}, 1)
+    signal.Notify(sigs, syscall.SIGWINCH)
+
+    go func() {
+        defer signal.Stop(sigs)
+        for {
+            select {
+            case <-sigs:
+                select {
+                case ch <- struct{}{}:
+                default:
+                }
+            case <-ctx.Done():
+                return
+            }
+        }
+    }()
+
+    return ch
+}
+
+// RestoreOnSignal restores the terminal and exits when the process is
+// interrupted or terminated, until ctx is done
+func (t *Terminal) RestoreOnSignal(ctx context.Context) {
+    sigs := make(chan os.Signal, 1)
+    signal.Notify(sigs, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+    go func() {
+        defer signal.Stop(sigs)
+        select {
+        case sig := <-sigs:
+            t.Restore()
+            code := 1
+            if s, ok := sig.(syscall.Signal); ok {
+                code = 128 + int(s)
+            }
+            os.Exit(code)
+        case <-ctx.Done():
+        }
+    }()
+}
+
+// RestoreOnPanic restores the terminal if the calling goroutine panics, then
+// lets the panic continue. It only works when deferred directly at the top
+// of the goroutine it protects.
+func (t *Terminal) RestoreOnPanic() {
+    if r := recover(); r != nil {
+        t.Restore()
+        panic(r)
+    }
+}
@@ -0,0 +1,59 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package terminal
+
+import (
+    "context"
+    "errors"
+    "io"
+    "os"
+    "runtime"
+    "time"
+)
+
+// ErrUnsupported is returned by Open on platforms without a terminal
+// implementation. Headless games and maze validation do not need a
+// terminal and still work there.
+var ErrUnsupported = errors.New("terminal: not supported on " + runtime.GOOS)
+
+// Terminal is a terminal in cbreak mode. It cannot be opened on this
+// platform.
+type Terminal struct{}
+
+// Open always fails with ErrUnsupported on this platform
+func Open(in *os.File, out io.Writer) (*Terminal, error) {
+    return nil, ErrUnsupported
+}
+
+// Read reads raw input from the terminal
+func (t *Terminal) Read(p []byte) (int, error) {
+    return 0, ErrUnsupported
+}
+
+// Restore puts the terminal back into the mode it was in before Open
+func (t *Terminal) Restore() error {
+    return nil
+}
+
+// WaitForInput waits up to timeout for input to become available and
+// reports whether there is some
+func (t *Terminal) WaitForInput(timeout time.Duration) (bool, error) {
+    return false, ErrUnsupported
+}
+
+// Size returns the number of rows and columns of the terminal
+func (t *Terminal) Size() (rows, cols int, err error) {
+    return 0, 0, ErrUnsupported
+}
+
+// NotifyResize returns a channel that never receives, as resizes cannot be
+// watched on this platform
+func (t *Terminal) NotifyResize(ctx context.Context) <-chan struct{} {
+    return make(chan struct{})
+}
+
+// RestoreOnSignal does nothing, there is nothing to restore
+func (t *Terminal) RestoreOnSignal(ctx context.Context) {}
+
+// RestoreOnPanic lets the panic continue, there is nothing to restore
+func (t *Terminal) RestoreOnPanic() {}